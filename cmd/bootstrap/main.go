@@ -15,7 +15,7 @@ func main() {
 		generators.DriverNameFlag,
 		"../gofire",
 		"main",
-		"Gofire",
+		[]string{"Gofire"},
 	); err != nil {
 		log.Fatalln(err)
 	}
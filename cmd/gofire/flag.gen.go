@@ -1,5 +1,5 @@
 // THIS IS AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
-// Generated using github.com/1pkg/gofire 🔥 2021-10-25T19:12:53+02:00.
+// Generated using github.com/1pkg/gofire 🔥 2026-10-17T07:02:04Z.
 package main
 
 import (
@@ -14,9 +14,17 @@ import (
 func CommandGofireFlag(ctx context.Context) (err error) {
 	var driver *string
 	var pckg *string
+	var env_prefix *string
+	var all *bool
+	var chain *bool
+	var config *bool
+	var optional *bool
 	var a0 string
-	var a1 string
+	var a1 []string
 	if err = func(ctx context.Context) (err error) {
+		// reset command line flag set so the command could be run multiple times.
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+		flag.CommandLine.Usage = func() { flag.Usage() }
 		defer func() {
 			if err != nil {
 				flag.Usage()
@@ -26,8 +34,18 @@ func CommandGofireFlag(ctx context.Context) (err error) {
 		flag.StringVar(&driver_, "driver", "", " ")
 		var pckg_ string
 		flag.StringVar(&pckg_, "pckg", "", " ")
+		var env_prefix_ string
+		flag.StringVar(&env_prefix_, "env-prefix", "", " ")
+		var all_ bool
+		flag.BoolVar(&all_, "all", false, " ")
+		var chain_ bool
+		flag.BoolVar(&chain_, "chain", false, " ")
+		var config_ bool
+		flag.BoolVar(&config_, "config", false, " ")
+		var optional_ bool
+		flag.BoolVar(&optional_, "optional", false, " ")
 		flag.Usage = func() {
-			doc, usage, list := "Gofire 🔥 is command line interface generator tool.\nThe first required argument dir represents directory path of source package.\nThe second required argument fun represents source function name.\nOptional flag driver represents driver backend name, one of [flag, pflag, cobra, reftype, bubbletea], flag by default.\nOptional flag pckg represents source package name, useful if package name and directory is different, last element of dir by default.", "Gofire -driver=\"\" -pckg=\"\" arg0 arg1 [-help -h]", "func Gofire(ctx context.Context, driver, pckg *string, dir, fun string), -driver string (default \"\") -pckg string (default \"\") arg 0 string arg 1 string"
+			doc, usage, list := "Gofire 🔥 is command line interface generator tool.\nThe first required argument dir represents directory path of source package.\nThe rest of required arguments funs represent source function names, multiple functions produce a subcommand per function.\nOptional flag driver represents driver backend name, one of [flag, pflag, cobra, reftype, bubbletea], flag by default.\nOptional flag pckg represents source package name, useful if package name and directory is different, last element of dir by default.\nOptional flag all represents package mode, all exported functions of the package become subcommands and funs have to be omitted, false by default.\nOptional flag chain represents chaining mode, methods of the function result could be called after `then` argument, false by default.\nOptional flag env-prefix represents environment variables prefix, flags without env tag are bound to variables derived from the prefix and the flag path e.g. APP_DB_HOST, empty by default.\nOptional flag config represents configuration mode, generated cli accepts --config path of json, yaml or toml file and --print-config flags, false by default.\nOptional flag optional represents optional pointers mode, pointer flags that weren't provided stay nil, false by default.", "Gofire -all=false -chain=false -config=false -driver=\"\" -env-prefix=\"\" -optional=false -pckg=\"\" DIR FUNS... [-help -h]", "func Gofire(ctx context.Context, driver, pckg, envprefix *string, all, chain, config, optional *bool, dir string, funs ...string), -all bool (default false) -chain bool (default false) -config bool (default false) -driver string (default \"\") -env-prefix string (default \"\") -optional bool (default false) -pckg string (default \"\") DIR string FUNS... string"
 			if doc != "" {
 				_, _ = fmt.Fprintln(flag.CommandLine.Output(), doc)
 			}
@@ -48,24 +66,40 @@ func CommandGofireFlag(ctx context.Context) (err error) {
 			pckg = &v
 		}
 		{
-			const i = 0
-			if flag.NArg() <= i {
-				return fmt.Errorf("argument %d-th is required", i)
-			}
-			a0 = flag.Arg(i)
+			v := string(env_prefix_)
+			env_prefix = &v
+		}
+		{
+			v := bool(all_)
+			all = &v
+		}
+		{
+			v := bool(chain_)
+			chain = &v
 		}
 		{
-			const i = 1
+			v := bool(config_)
+			config = &v
+		}
+		{
+			v := bool(optional_)
+			optional = &v
+		}
+		{
+			const i, arg = 0, "DIR"
 			if flag.NArg() <= i {
-				return fmt.Errorf("argument %d-th is required", i)
+				return fmt.Errorf("argument %s is required", arg)
 			}
-			a1 = flag.Arg(i)
+			a0 = flag.Arg(i)
+		}
+		for i := 1; i < flag.NArg(); i++ {
+			a1 = append(a1, flag.Arg(i))
 		}
 		return
 	}(ctx); err != nil {
 		return
 	}
-	Gofire(ctx, driver, pckg, a0, a1)
+	Gofire(ctx, driver, pckg, env_prefix, all, chain, config, optional, a0, a1...)
 	return
 }
 
@@ -16,10 +16,17 @@ import (
 
 // Gofire 🔥 is command line interface generator tool.
 // The first required argument dir represents directory path of source package.
-// The second required argument fun represents source function name.
+// The rest of required arguments funs represent source function names, multiple functions produce a subcommand per function.
 // Optional flag driver represents driver backend name, one of [flag, pflag, cobra, reftype, bubbletea], flag by default.
 // Optional flag pckg represents source package name, useful if package name and directory is different, last element of dir by default.
-func Gofire(ctx context.Context, driver, pckg *string, dir, fun string) {
+// Optional flag all represents package mode, all exported functions of the package become subcommands and funs have to be omitted, false by default.
+// Optional flag chain represents chaining mode, methods of the function result could be called after `then` argument, false by default.
+// Optional flag env-prefix represents environment variables prefix, flags without env tag are bound to variables derived from the prefix and the flag path e.g. APP_DB_HOST, empty by default.
+// Optional flag config represents configuration mode, generated cli accepts --config path of json, yaml or toml file and --print-config flags, false by default.
+// Optional flag optional represents optional pointers mode, pointer flags that weren't provided stay nil, false by default.
+//
+//gofire:param envprefix name=env-prefix
+func Gofire(ctx context.Context, driver, pckg, envprefix *string, all, chain, config, optional *bool, dir string, funs ...string) {
 	var d = "flag"
 	if *driver == "" {
 		driver = &d
@@ -28,7 +35,29 @@ func Gofire(ctx context.Context, driver, pckg *string, dir, fun string) {
 		p := filepath.Base(dir)
 		pckg = &p
 	}
-	p, err := cmd.Run(ctx, generators.DriverName(*driver), dir, *pckg, fun)
+	var opts []cmd.Option
+	if *chain {
+		opts = append(opts, cmd.WithChain())
+	}
+	if *envprefix != "" {
+		opts = append(opts, cmd.WithEnvPrefix(*envprefix))
+	}
+	if *config {
+		opts = append(opts, cmd.WithConfig())
+	}
+	if *optional {
+		opts = append(opts, cmd.WithOptional())
+	}
+	var p string
+	var err error
+	switch {
+	case *all && len(funs) > 0:
+		log.Fatal("functions can't be provided in package mode")
+	case *all:
+		p, err = cmd.RunPackage(ctx, generators.DriverName(*driver), dir, *pckg, opts...)
+	default:
+		p, err = cmd.Run(ctx, generators.DriverName(*driver), dir, *pckg, funs, opts...)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -4,30 +4,247 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/1pkg/gofire"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/parsers"
 )
 
-// Run first parse provided package function, then
+// Option defines optional generation behavior.
+type Option func(*options)
+
+type options struct {
+	chain     bool
+	envPrefix string
+	config    bool
+	optional  bool
+}
+
+// WithChain enables python-fire like chaining, so methods of the function result
+// could be called from the generated cli by trailing `then method` arguments.
+func WithChain() Option {
+	return func(o *options) {
+		o.chain = true
+	}
+}
+
+// WithEnvPrefix binds every flag without explicit env tag to the environment variable
+// derived from the prefix and the flag path, e.g. APP_DB_HOST for db.host flag and APP prefix.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithConfig enables configuration file loading, so the generated cli accepts
+// --config path flag to fill flags by their dotted names from json, yaml or toml file
+// and --print-config flag to print the effective configuration.
+func WithConfig() Option {
+	return func(o *options) {
+		o.config = true
+	}
+}
+
+// WithOptional makes every pointer flag optional, so the pointer flags
+// that weren't provided stay nil instead of pointing to their defaults.
+func WithOptional() Option {
+	return func(o *options) {
+		o.optional = true
+	}
+}
+
+// Run first parse provided package functions, then
 // generates relevant cli boilerplate and writes it to a file.
-func Run(ctx context.Context, name generators.DriverName, dir, pckg, function string) (string, error) {
-	cmd, err := parsers.Parse(ctx, os.DirFS(dir), pckg, function)
+// In case multiple functions are provided cli boilerplate
+// is generated as a commands tree with a subcommand per function.
+func Run(ctx context.Context, name generators.DriverName, dir, pckg string, functions []string, opts ...Option) (string, error) {
+	tree, err := parsers.ParseTree(ctx, parsers.DirFS(dir), pckg, functions...)
 	if err != nil {
 		return "", err
 	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for i := range tree.Commands {
+		tree.Commands[i] = env(tree.Commands[i], o.envPrefix)
+		tree.Commands[i] = optional(tree.Commands[i], o.optional)
+		tree.Commands[i].Config = o.config
+	}
+	// Chained methods are dispatched by the single command main entrypoint, so commands trees can't be chained.
+	if o.chain && len(tree.Commands) != 1 {
+		return "", fmt.Errorf("chaining is supported only for a single function, got %d commands in tree %s", len(tree.Commands), tree.Name)
+	}
 	var b bytes.Buffer
-	if err := generators.Generate(ctx, name, *cmd, &b); err != nil {
+	if len(tree.Commands) == 1 {
+		cmd := tree.Commands[0]
+		if o.chain {
+			cmd.Chain = chain(ctx, name, cmd.Chain)
+		} else {
+			cmd.Chain = nil
+		}
+		if err := generators.Generate(ctx, name, cmd, &b); err != nil {
+			return "", err
+		}
+	} else {
+		if err := generators.GenerateTree(ctx, name, rename(dir, *tree), &b); err != nil {
+			return "", err
+		}
+	}
+	return write(name, dir, b.Bytes())
+}
+
+// RunPackage first parse all exported package functions, then
+// generates relevant cli boilerplate as a commands tree and writes it to a file.
+// Functions that are not supported by provided driver are skipped and reported.
+func RunPackage(ctx context.Context, name generators.DriverName, dir, pckg string, opts ...Option) (string, error) {
+	tree, err := parsers.ParsePackage(ctx, parsers.DirFS(dir), pckg)
+	if err != nil {
 		return "", err
 	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.chain {
+		return "", fmt.Errorf("chaining is not supported in package mode, got package %s", pckg)
+	}
+	cmds := make([]gofire.Command, 0, len(tree.Commands))
+	for _, cmd := range tree.Commands {
+		cmd = env(cmd, o.envPrefix)
+		cmd = optional(cmd, o.optional)
+		cmd.Config = o.config
+		// Try to generate standalone command first to check if driver supports it.
+		if err := generators.Generate(ctx, name, cmd, io.Discard); err != nil {
+			log.Printf("function %s is skipped, %v", cmd.Function, err)
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	if len(cmds) == 0 {
+		return "", fmt.Errorf("no exported functions in package %s are supported by driver %s", pckg, name)
+	}
+	tree.Commands = cmds
+	var b bytes.Buffer
+	if err := generators.GenerateTree(ctx, name, rename(dir, *tree), &b); err != nil {
+		return "", err
+	}
+	return write(name, dir, b.Bytes())
+}
+
+// env derives environment variables names for all command flags without explicit env tag,
+// the names are derived from the prefix and the flag path e.g. APP_DB_HOST for db.host flag.
+// Repeatable groups flags can't be bound to a single environment variable so they are skipped.
+func env(cmd gofire.Command, prefix string) gofire.Command {
+	if prefix == "" {
+		return cmd
+	}
+	derive := func(path string) string {
+		return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(prefix + "_" + path))
+	}
+	params := make([]gofire.Parameter, 0, len(cmd.Parameters))
+	for _, p := range cmd.Parameters {
+		switch tp := p.(type) {
+		case gofire.Flag:
+			if tp.Env == "" {
+				tp.Env = derive(tp.Full)
+			}
+			p = tp
+		case gofire.Group:
+			if tp.Repeatable {
+				break
+			}
+			flags := make([]gofire.Flag, 0, len(tp.Flags))
+			for _, f := range tp.Flags {
+				if f.Env == "" {
+					f.Env = derive(tp.Name + "." + f.Full)
+				}
+				flags = append(flags, f)
+			}
+			tp.Flags = flags
+			p = tp
+		}
+		params = append(params, p)
+	}
+	cmd.Parameters = params
+	for i := range cmd.Chain {
+		cmd.Chain[i] = env(cmd.Chain[i], prefix)
+	}
+	return cmd
+}
+
+// optional makes all command pointer flags optional, repeatable groups flags
+// are skipped as optional flags are not supported for them.
+func optional(cmd gofire.Command, enabled bool) gofire.Command {
+	if !enabled {
+		return cmd
+	}
+	pointer := func(f gofire.Flag) gofire.Flag {
+		if _, ok := f.Type.(gofire.TPtr); ok {
+			f.Optional = true
+		}
+		return f
+	}
+	params := make([]gofire.Parameter, 0, len(cmd.Parameters))
+	for _, p := range cmd.Parameters {
+		switch tp := p.(type) {
+		case gofire.Flag:
+			p = pointer(tp)
+		case gofire.Group:
+			if tp.Repeatable {
+				break
+			}
+			flags := make([]gofire.Flag, 0, len(tp.Flags))
+			for _, f := range tp.Flags {
+				flags = append(flags, pointer(f))
+			}
+			tp.Flags = flags
+			p = tp
+		}
+		params = append(params, p)
+	}
+	cmd.Parameters = params
+	for i := range cmd.Chain {
+		cmd.Chain[i] = optional(cmd.Chain[i], enabled)
+	}
+	return cmd
+}
+
+// chain filters out chained methods that are not supported by provided driver.
+func chain(ctx context.Context, name generators.DriverName, links []gofire.Command) []gofire.Command {
+	supported := make([]gofire.Command, 0, len(links))
+	for _, link := range links {
+		if err := generators.Generate(ctx, name, link, io.Discard); err != nil {
+			log.Printf("method %s is skipped, %v", link.Function, err)
+			continue
+		}
+		supported = append(supported, link)
+	}
+	return supported
+}
+
+// rename uses directory name as the tree name for main package.
+func rename(dir string, tree gofire.Tree) gofire.Tree {
+	if tree.Name == "main" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			tree.Name = filepath.Base(abs)
+		}
+	}
+	return tree
+}
+
+func write(name generators.DriverName, dir string, b []byte) (string, error) {
 	p := filepath.Join(dir, fmt.Sprintf("%s.gen.go", name))
 	f, err := os.Create(p)
 	if err != nil {
 		return "", err
 	}
-	if _, err := f.Write(b.Bytes()); err != nil {
+	if _, err := f.Write(b); err != nil {
 		return "", err
 	}
 	if err := f.Close(); err != nil {
@@ -0,0 +1,82 @@
+package cmd_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/1pkg/gofire/cmd"
+	"github.com/1pkg/gofire/generators"
+	_ "github.com/1pkg/gofire/generators/flag"
+)
+
+func TestRunPackageTwice(t *testing.T) {
+	dir := t.TempDir()
+	src := `
+		// Package calc is a simple calculator.
+		package calc
+
+		// Add sums two numbers.
+		func Add(a, b int) int {
+			return a + b
+		}
+
+		// Neg negates a number.
+		func Neg(a int) int {
+			return -a
+		}
+	`
+	if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var outs []string
+	for i := 0; i < 2; i++ {
+		p, err := cmd.RunPackage(context.TODO(), generators.DriverNameFlag, dir, "calc")
+		if err != nil {
+			t.Fatalf("run package %d should not fail %v", i, err)
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outs = append(outs, strings.SplitN(string(b), "\n", 3)[2])
+	}
+	// The generated file header holds the generation time, so it's excluded from the comparison.
+	if outs[0] != outs[1] {
+		t.Fatalf("run package should produce the same output on the second run, got %q and %q", outs[0], outs[1])
+	}
+	if strings.Contains(outs[1], "CommandCommandAddFlag") {
+		t.Fatalf("run package should not expose generated commands as subcommands, got %q", outs[1])
+	}
+}
+
+func TestRunChainTree(t *testing.T) {
+	dir := t.TempDir()
+	src := `
+		package calc
+
+		// Add sums two numbers.
+		func Add(a, b int) int {
+			return a + b
+		}
+
+		// Neg negates a number.
+		func Neg(a int) int {
+			return -a
+		}
+	`
+	if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := cmd.Run(context.TODO(), generators.DriverNameFlag, dir, "calc", []string{"Add", "Neg"}, cmd.WithChain())
+	if expected := "chaining is supported only for a single function, got 2 commands in tree calc"; fmt.Sprintf("%v", err) != expected {
+		t.Fatalf("expected error message %q but got %q", expected, err)
+	}
+	_, err = cmd.RunPackage(context.TODO(), generators.DriverNameFlag, dir, "calc", cmd.WithChain())
+	if expected := "chaining is not supported in package mode, got package calc"; fmt.Sprintf("%v", err) != expected {
+		t.Fatalf("expected error message %q but got %q", expected, err)
+	}
+}
@@ -23,9 +23,15 @@ func (p Placeholder) Accept(v Visitor) error {
 
 // Argument is a cmd parameter implementation
 // that represents cmd positional argument.
+// Argument with Name is shown under this name in the usage.
+// Optional argument falls back to its Default value if it isn't provided.
 type Argument struct {
 	Index    uint64
 	Ellipsis bool
+	Name     string
+	Doc      string
+	Optional bool
+	Default  interface{}
 	Type     Typ
 }
 
@@ -34,13 +40,36 @@ func (a Argument) Accept(v Visitor) error {
 }
 
 // Flag is a cmd parameter implementation
-// that represents cmd flag.
+// that represents cmd flag. Flag with Env
+// falls back to the environment variable value
+// if the flag itself isn't provided.
+// Required flag has to be provided either explicitly
+// or through the environment variable or configuration.
+// Flag with Enum accepts only one of the enum values.
+// Flag with Min, Max, Pattern, MinLen or MaxLen accepts only values
+// within the range, matching the pattern or with the length within the range.
+// Flags sharing the same Xor group are mutually exclusive and flags
+// sharing the same With group have to be provided together.
+// Flag with Implies requires the implied flag of the same group to be provided along with it.
+// Optional pointer flag stays nil if the flag isn't provided.
 type Flag struct {
 	Full       string
 	Short      string
 	Doc        string
+	Env        string
+	Enum       []string
+	Min        string
+	Max        string
+	Pattern    string
+	MinLen     string
+	MaxLen     string
+	Xor        string
+	With       string
+	Implies    string
 	Deprecated bool
 	Hidden     bool
+	Required   bool
+	Optional   bool
 	Default    interface{}
 	Type       Typ
 }
@@ -49,13 +78,35 @@ func (f Flag) Accept(v Visitor) error {
 	return v.VisitFlag(f, nil)
 }
 
+// Choices returns the flag allowed values, either the enum values
+// or the constants names of the flag type if the type has them.
+func (f Flag) Choices() []string {
+	if len(f.Enum) > 0 {
+		return f.Enum
+	}
+	typ := f.Type
+	if t, ok := typ.(TPtr); ok {
+		typ = t.ETyp
+	}
+	if t, ok := typ.(TText); ok {
+		return t.Names()
+	}
+	return nil
+}
+
 // Group is a cmd parameter implementation
 // that groups multiple cmd flags together.
+// Repeatable group represents a slice of the group type
+// where each flag is repeated for every slice index.
+// Persistent group flags are shared by all the commands of a tree,
+// e.g. method receiver group flags are shared by all the type methods.
 type Group struct {
-	Name  string
-	Doc   string
-	Flags []Flag
-	Type  Typ
+	Name       string
+	Doc        string
+	Flags      []Flag
+	Type       Typ
+	Repeatable bool
+	Persistent bool
 }
 
 func (g Group) Accept(v Visitor) error {
@@ -67,19 +118,49 @@ func (g Group) Accept(v Visitor) error {
 	return nil
 }
 
-// Group is a cmd composite parameter implementation
-// that represent function as a command.
+// Command is a cmd composite parameter implementation
+// that represent function or method as a command.
+// For methods the receiver is represented by the flags group.
+// Chain contains methods of the first result type that could be called on the command result.
+// Config enables loading the command flags values from a configuration file.
+// Command with Name is invoked and shown under this name instead of the function name,
+// Aliases are the alternative command names, Long is the long command description
+// shown instead of the doc, Examples are the command usage examples and
+// Hidden command is not listed among the other commands.
+// Silent command results are not printed, e.g. for functions that do their own output.
 type Command struct {
 	Package    string
 	Function   string
+	Receiver   *Group
 	Definition string
 	Doc        string
+	Name       string
+	Aliases    []string
+	Long       string
+	Examples   []string
+	Hidden     bool
+	Silent     bool
 	Context    bool
 	Results    []string
 	Parameters []Parameter
+	Chain      []Command
+	Config     bool
+}
+
+// Title returns the command name as it's invoked and shown, either the name or the function name.
+func (c Command) Title() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Function
 }
 
 func (c Command) Accept(v Visitor) error {
+	if c.Receiver != nil {
+		if err := c.Receiver.Accept(v); err != nil {
+			return err
+		}
+	}
 	for _, p := range c.Parameters {
 		if err := p.Accept(v); err != nil {
 			return err
@@ -87,3 +168,12 @@ func (c Command) Accept(v Visitor) error {
 	}
 	return nil
 }
+
+// Tree is a cmd composite implementation
+// that represent multiple functions as subcommands of a root command.
+type Tree struct {
+	Package  string
+	Name     string
+	Doc      string
+	Commands []Command
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Flag is the name of the flag that holds the configuration file path.
+	Flag = "config"
+	// PrintFlag is the name of the flag that requests to print the effective configuration.
+	PrintFlag = "print-config"
+)
+
+// reserved checks whether the flag name is reserved by the generated command itself,
+// so it can be neither loaded from nor printed to the configuration.
+func reserved(name string) bool {
+	return name == Flag || name == PrintFlag || name == "help"
+}
+
+// Load reads the configuration file and flattens it into values by dotted keys, e.g. db.host,
+// so the values could be set directly to flags with the same names. The file format is detected
+// by the file extension and could be either json, yaml or toml. Nested objects produce dotted keys,
+// lists of scalars are joined as comma separated values and lists of objects produce indexed keys e.g. servers.0.host.
+// Yaml files are limited to block and flow mappings, block and flow sequences, single line plain
+// and quoted scalars and comments; toml files are limited to tables, arrays of tables, dotted keys,
+// single line strings, plain values, arrays, inline tables and comments. Any other syntax,
+// e.g. yaml block scalars, anchors, tags and multiple documents or toml multi-line strings and unquoted strings,
+// as well as duplicated keys and tables, is reported as an error.
+func Load(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s can't be read, %w", path, err)
+	}
+	var tree map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		err = dec.Decode(&tree)
+	case ".yaml", ".yml":
+		tree, err = yaml(string(b))
+	case ".toml":
+		tree, err = toml(string(b))
+	default:
+		return nil, fmt.Errorf("config file %s format %q is not supported", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s can't be parsed, %w", path, err)
+	}
+	values := make(map[string]string)
+	if err := flatten("", tree, values); err != nil {
+		return nil, fmt.Errorf("config file %s can't be parsed, %w", path, err)
+	}
+	return values, nil
+}
+
+// Apply sets the configuration values to the flags that weren't provided explicitly.
+// The defined reports whether the flag exists, the changed reports whether the flag was provided
+// and the set sets the flag value by name. The keys nested under a flag are collected into
+// the flag value as comma separated key=value pairs, so map flags could be configured as objects,
+// e.g. labels: {a: 1} sets --labels=a=1. Unknown keys are reported as errors.
+func Apply(values map[string]string, defined, changed func(string) bool, set func(string, string) error) error {
+	flags := make(map[string]string)
+	pairs := make(map[string][]string)
+	for _, key := range keys(values) {
+		if !reserved(key) && defined(key) {
+			flags[key] = values[key]
+			continue
+		}
+		name := mflag(key, defined)
+		if name == "" {
+			return fmt.Errorf("config key %s is unknown", key)
+		}
+		pairs[name] = append(pairs[name], strings.TrimPrefix(key, name+".")+"="+values[key])
+	}
+	for name, pairs := range pairs {
+		if _, ok := flags[name]; ok {
+			return fmt.Errorf("config key %s is defined multiple times", name)
+		}
+		flags[name] = Join(pairs)
+	}
+	for _, key := range keys(flags) {
+		if changed(key) {
+			continue
+		}
+		if err := set(key, flags[key]); err != nil {
+			return fmt.Errorf("config key %s parse error: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// mflag returns the longest dotted prefix of the key that is a defined flag or empty string if there is none.
+func mflag(key string, defined func(string) bool) string {
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		if name := key[:i]; !reserved(name) && defined(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Join joins the list items as a single line of comma separated values, the items that contain
+// commas or quotes are quoted, so list flags parse them back as the same items.
+func Join(items []string) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	// Writing to the buffer never fails.
+	_ = w.Write(items)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Print writes the values as json object nested by the values dotted keys,
+// so the printed configuration could be loaded back as a configuration file.
+func Print(w io.Writer, values map[string]string) error {
+	tree := make(map[string]interface{})
+	for _, key := range keys(values) {
+		if reserved(key) {
+			continue
+		}
+		node := tree
+		path := strings.Split(key, ".")
+		for _, name := range path[:len(path)-1] {
+			next, ok := node[name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				node[name] = next
+			}
+			node = next
+		}
+		node[path[len(path)-1]] = values[key]
+	}
+	b, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func keys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flatten(prefix string, v interface{}, values map[string]string) error {
+	key := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+	switch tv := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		for name, v := range tv {
+			if err := flatten(key(name), v, values); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		scalars := make([]string, 0, len(tv))
+		for i, v := range tv {
+			switch v.(type) {
+			case map[string]interface{}:
+				if err := flatten(key(strconv.Itoa(i)), v, values); err != nil {
+					return err
+				}
+			case []interface{}:
+				return fmt.Errorf("config key %s nested lists are not supported", prefix)
+			case nil:
+				return fmt.Errorf("config key %s null list items are not supported", prefix)
+			default:
+				scalars = append(scalars, fmt.Sprint(v))
+			}
+		}
+		if len(scalars) > 0 {
+			if len(scalars) != len(tv) {
+				return fmt.Errorf("config key %s mixed lists are not supported", prefix)
+			}
+			values[prefix] = Join(scalars)
+		}
+	default:
+		values[prefix] = fmt.Sprint(tv)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	table := map[string]struct {
+		name   string
+		data   string
+		values map[string]string
+		err    error
+	}{
+		"valid json config should produce expected values": {
+			name: "config.json",
+			data: `{
+				"verbose": true,
+				"db": {"host": "localhost", "port": 5432, "timeout": 1.5},
+				"tags": ["a", "b"],
+				"servers": [{"host": "a"}, {"host": "b", "port": 80}],
+				"empty": null
+			}`,
+			values: map[string]string{
+				"verbose":        "true",
+				"db.host":        "localhost",
+				"db.port":        "5432",
+				"db.timeout":     "1.5",
+				"tags":           "a,b",
+				"servers.0.host": "a",
+				"servers.1.host": "b",
+				"servers.1.port": "80",
+			},
+		},
+		"valid yaml config should produce expected values": {
+			name: "config.yaml",
+			data: `
+# service config.
+verbose: true
+db:
+  host: "local#host" # db host.
+  port: 5432
+  url: http://localhost:5432
+tags: [a, 'b c']
+list:
+  - x
+  - y
+servers:
+  - host: a
+  - host: b
+    port: 80
+`,
+			values: map[string]string{
+				"verbose":        "true",
+				"db.host":        "local#host",
+				"db.port":        "5432",
+				"db.url":         "http://localhost:5432",
+				"tags":           "a,b c",
+				"list":           "x,y",
+				"servers.0.host": "a",
+				"servers.1.host": "b",
+				"servers.1.port": "80",
+			},
+		},
+		"valid toml config should produce expected values": {
+			name: "config.toml",
+			data: `
+# service config.
+verbose = true
+opts.retries = 3
+
+[db]
+host = "localhost" # db host.
+port = 5432
+tags = [
+  "a",
+  "b",
+]
+limits = { min = 1, max = 10 }
+
+[[servers]]
+host = 'a'
+
+[servers.tls]
+since = 07:32:00
+
+[[servers]]
+host = "b"
+port = 80
+
+[servers.tls]
+since = 1979-05-27 07:32:00Z
+`,
+			values: map[string]string{
+				"verbose":             "true",
+				"opts.retries":        "3",
+				"db.host":             "localhost",
+				"db.port":             "5432",
+				"db.tags":             "a,b",
+				"db.limits.min":       "1",
+				"db.limits.max":       "10",
+				"servers.0.host":      "a",
+				"servers.0.tls.since": "07:32:00",
+				"servers.1.host":      "b",
+				"servers.1.port":      "80",
+				"servers.1.tls.since": "1979-05-27 07:32:00Z",
+			},
+		},
+		"valid yaml config with flow mappings and quoted list items should produce expected values": {
+			name: "config.yaml",
+			data: `
+labels: {a: 1, 'b': "x, y"}
+db: {host: localhost, opts: {tls: true}}
+tags: ["a,b", c]
+`,
+			values: map[string]string{
+				"labels.a":    "1",
+				"labels.b":    "x, y",
+				"db.host":     "localhost",
+				"db.opts.tls": "true",
+				"tags":        `"a,b",c`,
+			},
+		},
+		"valid toml config with literal strings should produce expected values": {
+			name: "config.toml",
+			data: `
+path = 'C:\dir\'
+quote = 'say "hi"'
+empty = ''
+`,
+			values: map[string]string{
+				"path":  `C:\dir\`,
+				"quote": `say "hi"`,
+				"empty": "",
+			},
+		},
+		"invalid yaml config indentation should produce expected error": {
+			name: "config.yml",
+			data: "db:\n  host: a\n    port: 1\n",
+			err:  errors.New("config file config.yml can't be parsed, yaml line 3 unexpected indentation"),
+		},
+		"unsupported yaml config block scalar should produce expected error": {
+			name: "config.yaml",
+			data: "doc: |\n  text\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 1 block scalars are not supported, got |"),
+		},
+		"unsupported yaml config multi-line scalar should produce expected error": {
+			name: "config.yaml",
+			data: "doc: multi\n  line\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 2 multi-line scalars are not supported"),
+		},
+		"unsupported yaml config anchor should produce expected error": {
+			name: "config.yaml",
+			data: "db: &db\n  host: a\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 1 anchors, aliases and tags are not supported, got &db"),
+		},
+		"unsupported toml config multi-line string should produce expected error": {
+			name: "config.toml",
+			data: "doc = \"\"\"\ntext\n\"\"\"\n",
+			err:  errors.New(`config file config.toml can't be parsed, toml line 1 multi-line strings are not supported, got """`),
+		},
+		"invalid toml config literal string should produce expected error": {
+			name: "config.toml",
+			data: "name = 'it''s'\n",
+			err:  errors.New("config file config.toml can't be parsed, toml line 1 invalid literal string 'it''s'"),
+		},
+		"invalid toml config duplicated keys should produce expected error": {
+			name: "config.toml",
+			data: "[db]\nhost = 'a'\nhost = 'b'\n",
+			err:  errors.New("config file config.toml can't be parsed, toml line 3 key host is defined multiple times"),
+		},
+		"unsupported yaml config multiple documents should produce expected error": {
+			name: "config.yaml",
+			data: "---\nhost: a\n---\nhost: b\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 3 multiple documents are not supported"),
+		},
+		"invalid yaml config duplicated keys should produce expected error": {
+			name: "config.yaml",
+			data: "db:\n  host: a\n  host: b\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 3 key host is defined multiple times"),
+		},
+		"invalid yaml config plain scalar mapping should produce expected error": {
+			name: "config.yaml",
+			data: "db: host: a\n",
+			err:  errors.New("config file config.yaml can't be parsed, yaml line 1 mapping values are not allowed in plain scalar host: a"),
+		},
+		"invalid yaml config null list items should produce expected error": {
+			name: "config.yaml",
+			data: "tags: [a, ~]\n",
+			err:  errors.New("config file config.yaml can't be parsed, config key tags null list items are not supported"),
+		},
+		"invalid toml config unquoted string should produce expected error": {
+			name: "config.toml",
+			data: "host = localhost\n",
+			err:  errors.New("config file config.toml can't be parsed, toml line 1 invalid value localhost, strings have to be quoted"),
+		},
+		"invalid toml config bare key should produce expected error": {
+			name: "config.toml",
+			data: "db host = 'a'\n",
+			err:  errors.New("config file config.toml can't be parsed, toml line 1 invalid bare key db host"),
+		},
+		"invalid toml config duplicated tables should produce expected error": {
+			name: "config.toml",
+			data: "[db]\nhost = 'a'\n[db]\nport = 1\n",
+			err:  errors.New("config file config.toml can't be parsed, toml line 3 table db is defined multiple times"),
+		},
+		"unsupported config format should produce expected error": {
+			name: "config.ini",
+			data: "host=a",
+			err:  errors.New(`config file config.ini format ".ini" is not supported`),
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tcase.name), []byte(tcase.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			values, err := Load(filepath.Join(dir, tcase.name))
+			if err != nil {
+				err = errors.New(strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), ""))
+			}
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if !reflect.DeepEqual(tcase.values, values) {
+				t.Fatalf("expected values %v but got %v", tcase.values, values)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	flags := map[string]bool{"verbose": false, "labels": false, "tags": false, "db.host": true}
+	table := map[string]struct {
+		values map[string]string
+		set    map[string]string
+		err    error
+	}{
+		"values should be set only to the flags that weren't provided": {
+			values: map[string]string{"verbose": "true", "db.host": "remote", "tags": `"a,b",c`},
+			set:    map[string]string{"verbose": "true", "tags": `"a,b",c`},
+		},
+		"nested values should be collected into map flags": {
+			values: map[string]string{"labels.a": "1", "labels.b.c": "x, y"},
+			set:    map[string]string{"labels": `a=1,"b.c=x, y"`},
+		},
+		"nested values along with map flag value should produce expected error": {
+			values: map[string]string{"labels": "a=1", "labels.b": "2"},
+			err:    errors.New("config key labels is defined multiple times"),
+		},
+		"unknown values should produce expected error": {
+			values: map[string]string{"db.port": "5432"},
+			err:    errors.New("config key db.port is unknown"),
+		},
+		"reserved values should produce expected error": {
+			values: map[string]string{"print-config": "true"},
+			err:    errors.New("config key print-config is unknown"),
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			set := make(map[string]string)
+			err := Apply(
+				tcase.values,
+				func(name string) bool { _, ok := flags[name]; return ok },
+				func(name string) bool { return flags[name] },
+				func(name, v string) error { set[name] = v; return nil },
+			)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if tcase.err == nil && !reflect.DeepEqual(tcase.set, set) {
+				t.Fatalf("expected set values %v but got %v", tcase.set, set)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	values := map[string]string{"verbose": "true", "db.host": "localhost", "db.port": "5432"}
+	if err := Print(&buf, values); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "db": {
+    "host": "localhost",
+    "port": "5432"
+  },
+  "verbose": "true"
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("expected output %q but got %q", expected, buf.String())
+	}
+}
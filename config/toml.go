@@ -0,0 +1,220 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// toml parses the subset of toml that is enough for the configuration files:
+// tables, arrays of tables, dotted keys, single line strings, plain values, arrays, inline tables and comments.
+// Multi-line strings, invalid bare keys and values, duplicated keys and tables are reported as errors.
+func toml(src string) (map[string]interface{}, error) {
+	tree := make(map[string]interface{})
+	table := tree
+	tables := make(map[string]bool)
+	lines := strings.Split(src, "\n")
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimSpace(uncomment(lines[i]))
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "[["):
+			if !strings.HasSuffix(line, "]]") {
+				return nil, fmt.Errorf("toml line %d unterminated array table %s", n, line)
+			}
+			path, err := tkeys(line[2 : len(line)-2])
+			if err != nil {
+				return nil, fmt.Errorf("toml line %d %w", n, err)
+			}
+			parent, err := tnode(tree, path[:len(path)-1])
+			if err != nil {
+				return nil, fmt.Errorf("toml line %d %w", n, err)
+			}
+			name := path[len(path)-1]
+			list, ok := parent[name].([]interface{})
+			if parent[name] != nil && !ok {
+				return nil, fmt.Errorf("toml line %d key %s is not an array table", n, name)
+			}
+			table = make(map[string]interface{})
+			parent[name] = append(list, table)
+			// Every array table element starts its own set of sub tables.
+			prefix := strings.Join(path, ".") + "."
+			for t := range tables {
+				if strings.HasPrefix(t, prefix) {
+					delete(tables, t)
+				}
+			}
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("toml line %d unterminated table %s", n, line)
+			}
+			path, err := tkeys(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("toml line %d %w", n, err)
+			}
+			// Tables could be defined only once, except for the sub tables of the arrays of tables.
+			name := strings.Join(path, ".")
+			if tables[name] {
+				return nil, fmt.Errorf("toml line %d table %s is defined multiple times", n, name)
+			}
+			tables[name] = true
+			if table, err = tnode(tree, path); err != nil {
+				return nil, fmt.Errorf("toml line %d %w", n, err)
+			}
+		default:
+			// Multiline arrays are joined until all the brackets are closed.
+			for strings.Count(line, "[") > strings.Count(line, "]") && i+1 < len(lines) {
+				i++
+				line += " " + strings.TrimSpace(uncomment(lines[i]))
+			}
+			if err := tpair(table, line); err != nil {
+				return nil, fmt.Errorf("toml line %d %w", n, err)
+			}
+		}
+	}
+	return tree, nil
+}
+
+// tpair parses key value pair and sets it to the table.
+func tpair(table map[string]interface{}, line string) error {
+	key, value := kv(line, "=")
+	if key == line {
+		return fmt.Errorf("key value pair is expected, got %s", line)
+	}
+	path, err := tkeys(key)
+	if err != nil {
+		return err
+	}
+	node, err := tnode(table, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	v, err := tvalue(value)
+	if err != nil {
+		return err
+	}
+	name := path[len(path)-1]
+	if _, ok := node[name]; ok {
+		return fmt.Errorf("key %s is defined multiple times", key)
+	}
+	node[name] = v
+	return nil
+}
+
+// tvalue parses toml value, all scalars are kept as strings.
+func tvalue(value string) (interface{}, error) {
+	switch {
+	case value == "":
+		return nil, fmt.Errorf("value is expected")
+	case strings.HasPrefix(value, "{"):
+		if !strings.HasSuffix(value, "}") {
+			return nil, fmt.Errorf("unterminated inline table %s", value)
+		}
+		table := make(map[string]interface{})
+		inner := strings.TrimSpace(value[1 : len(value)-1])
+		if inner == "" {
+			return table, nil
+		}
+		for _, pair := range split(inner, ",") {
+			if err := tpair(table, strings.TrimSpace(pair)); err != nil {
+				return nil, err
+			}
+		}
+		return table, nil
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("unterminated array %s", value)
+		}
+		list := []interface{}{}
+		inner := strings.TrimSpace(value[1 : len(value)-1])
+		for _, item := range split(inner, ",") {
+			// Trailing commas are allowed in toml arrays.
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			v, err := tvalue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`):
+		return tunquote(value)
+	case !tplain.MatchString(value):
+		return nil, fmt.Errorf("invalid value %s, strings have to be quoted", value)
+	}
+	return value, nil
+}
+
+// tplain matches the plain toml values: booleans, numbers, dates and times.
+var tplain = regexp.MustCompile(`^(true|false|[+-]?(inf|nan)|[+-]?[0-9][0-9A-Za-z_.:+-]*|[0-9]{4}-[0-9]{2}-[0-9]{2}[ Tt][0-9:.]+([Zz]|[+-][0-9:]+)?)$`)
+
+// tbare matches the bare toml keys.
+var tbare = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tunquote unquotes basic or literal toml strings and keeps plain values as they are,
+// literal strings are kept as is since they have no escapes.
+func tunquote(text string) (string, error) {
+	switch {
+	case strings.HasPrefix(text, `"""`) || strings.HasPrefix(text, `'''`):
+		return "", fmt.Errorf("multi-line strings are not supported, got %s", text)
+	case len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'':
+		if strings.Contains(text[1:len(text)-1], "'") {
+			return "", fmt.Errorf("invalid literal string %s", text)
+		}
+		return text[1 : len(text)-1], nil
+	}
+	return unquote(text)
+}
+
+// tkeys splits dotted toml key into the path of unquoted keys.
+func tkeys(key string) ([]string, error) {
+	parts := split(key, ".")
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" && !strings.HasPrefix(part, `"`) && !strings.HasPrefix(part, `'`) && !tbare.MatchString(part) {
+			return nil, fmt.Errorf("invalid bare key %s", part)
+		}
+		name, err := tunquote(part)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("empty key in %s", key)
+		}
+		path = append(path, name)
+	}
+	return path, nil
+}
+
+// tnode returns the nested table by the path creating missing tables on the way,
+// for arrays of tables the last table of the array is used.
+func tnode(table map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for _, name := range path {
+		switch next := table[name].(type) {
+		case nil:
+			child := make(map[string]interface{})
+			table[name] = child
+			table = child
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			if len(next) == 0 {
+				return nil, fmt.Errorf("key %s is not a table", name)
+			}
+			last, ok := next[len(next)-1].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key %s is not a table", name)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("key %s is not a table", name)
+		}
+	}
+	return table, nil
+}
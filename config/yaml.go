@@ -0,0 +1,302 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type yline struct {
+	n      int
+	indent int
+	text   string
+}
+
+// yaml parses the subset of yaml that is enough for the configuration files:
+// single document of block and flow mappings, block and flow sequences, single line quoted and plain scalars and comments.
+// Block scalars, multi-line scalars, anchors, aliases, tags, directives, multiple documents
+// and duplicated keys are reported as errors.
+func yaml(src string) (map[string]interface{}, error) {
+	var lines []yline
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimRight(uncomment(line), " \t\r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" || (text == "---" && len(lines) == 0) {
+			continue
+		}
+		switch {
+		case text == "---" || text == "...":
+			return nil, fmt.Errorf("yaml line %d multiple documents are not supported", i+1)
+		case strings.HasPrefix(text, "%"):
+			return nil, fmt.Errorf("yaml line %d directives are not supported, got %s", i+1, text)
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("yaml line %d tabs can't be used for indentation", i+1)
+		}
+		lines = append(lines, yline{n: i + 1, indent: len(line) - len(text), text: text})
+	}
+	if len(lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	p := yparser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml line %d unexpected indentation", p.lines[p.pos].n)
+	}
+	tree, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("yaml document has to be a mapping")
+	}
+	return tree, nil
+}
+
+type yparser struct {
+	lines []yline
+	pos   int
+}
+
+func (p *yparser) block(indent int) (interface{}, error) {
+	if seq(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yparser) sequence(indent int) (interface{}, error) {
+	var list []interface{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent || !seq(line.text) {
+			return nil, fmt.Errorf("yaml line %d unexpected indentation", line.n)
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		switch {
+		case seq(item):
+			return nil, fmt.Errorf("yaml line %d nested sequences on the same line are not supported", line.n)
+		case item == "":
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				list = append(list, nil)
+				continue
+			}
+			v, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		case pair(item):
+			// Mapping inside sequence item starts on the same line as the item dash,
+			// so the line is shifted to the item content indentation and parsed as a mapping.
+			shift := indent + len(line.text) - len(strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " "))
+			p.lines[p.pos] = yline{n: line.n, indent: shift, text: item}
+			v, err := p.mapping(shift)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		default:
+			v, err := scalar(item)
+			if err != nil {
+				return nil, fmt.Errorf("yaml line %d %w", line.n, err)
+			}
+			list = append(list, v)
+			p.pos++
+		}
+	}
+	return list, nil
+}
+
+func (p *yparser) mapping(indent int) (interface{}, error) {
+	tree := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent && !pair(line.text) && !seq(line.text) {
+			return nil, fmt.Errorf("yaml line %d multi-line scalars are not supported", line.n)
+		}
+		if line.indent > indent || !pair(line.text) {
+			return nil, fmt.Errorf("yaml line %d unexpected indentation", line.n)
+		}
+		key, value := kv(line.text, ":")
+		key, err := unquote(key)
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d %w", line.n, err)
+		}
+		if _, ok := tree[key]; ok {
+			return nil, fmt.Errorf("yaml line %d key %s is defined multiple times", line.n, key)
+		}
+		p.pos++
+		if value != "" {
+			v, err := scalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("yaml line %d %w", line.n, err)
+			}
+			tree[key] = v
+			continue
+		}
+		// Block sequences are allowed on the same indentation as their mapping key.
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && seq(next.text)) {
+				v, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				tree[key] = v
+				continue
+			}
+		}
+		tree[key] = nil
+	}
+	return tree, nil
+}
+
+func seq(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func pair(text string) bool {
+	key, _ := kv(text, ":")
+	return key != text && (strings.HasSuffix(text, ":") || strings.Contains(text, ": "))
+}
+
+// kv splits the text into the key and the value by the first separator outside of quotes.
+func kv(text, sep string) (string, string) {
+	parts := split(text, sep)
+	if len(parts) < 2 {
+		return text, ""
+	}
+	key := strings.TrimSpace(parts[0])
+	return key, strings.TrimSpace(strings.TrimPrefix(text, parts[0]+sep))
+}
+
+// scalar parses plain or quoted scalar value, flow sequence of scalars or flow mapping.
+func scalar(text string) (interface{}, error) {
+	switch text[0] {
+	case '|', '>':
+		return nil, fmt.Errorf("block scalars are not supported, got %s", text)
+	case '&', '*', '!':
+		return nil, fmt.Errorf("anchors, aliases and tags are not supported, got %s", text)
+	case '{':
+		if !strings.HasSuffix(text, "}") {
+			return nil, fmt.Errorf("unterminated mapping %s", text)
+		}
+		tree := make(map[string]interface{})
+		for _, item := range split(text[1:len(text)-1], ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			key, value := kv(item, ":")
+			if key == item || value == "" {
+				return nil, fmt.Errorf("mapping entry is expected, got %s", item)
+			}
+			key, err := unquote(key)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := tree[key]; ok {
+				return nil, fmt.Errorf("key %s is defined multiple times", key)
+			}
+			if tree[key], err = scalar(value); err != nil {
+				return nil, err
+			}
+		}
+		return tree, nil
+	}
+	if strings.HasPrefix(text, "[") {
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unterminated list %s", text)
+		}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		list := []interface{}{}
+		if inner == "" {
+			return list, nil
+		}
+		for _, item := range split(inner, ",") {
+			// Trailing commas are allowed in yaml flow sequences.
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			v, err := scalar(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	}
+	if text == "~" || text == "null" {
+		return nil, nil
+	}
+	// Plain scalars can't hold mapping values, so they are most likely broken mappings.
+	if text[0] != '"' && text[0] != '\'' && pair(text) {
+		return nil, fmt.Errorf("mapping values are not allowed in plain scalar %s", text)
+	}
+	return unquote(text)
+}
+
+// unquote unquotes double or single quoted strings and keeps plain strings as they are.
+func unquote(text string) (string, error) {
+	switch {
+	case len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"':
+		return strconv.Unquote(text)
+	case len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'':
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case strings.HasPrefix(text, `"`) || strings.HasPrefix(text, `'`):
+		return "", fmt.Errorf("unterminated string %s", text)
+	}
+	return text, nil
+}
+
+// uncomment strips the comment from the line, comments inside quotes are kept.
+func uncomment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// split splits the text by the separator outside of quotes and brackets.
+func split(text, sep string) []string {
+	var parts []string
+	var quote rune
+	var depth, last int
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(text[i:], sep):
+			if i >= last {
+				parts = append(parts, text[last:i])
+				last = i + len(sep)
+			}
+		}
+	}
+	return append(parts, text[last:])
+}
@@ -42,7 +42,7 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 			return "", err
 		}
 	}
-	if _, err := fmt.Fprintf(&buf, `m.doc = %q;`, cmd.Doc+"\n"+cmd.Definition); err != nil {
+	if _, err := fmt.Fprintf(&buf, `m.doc = %q;`, internal.Manual(cmd)+"\n"+cmd.Definition); err != nil {
 		return "", err
 	}
 	if _, err := buf.WriteString(
@@ -168,7 +168,7 @@ func (d driver) Template() string {
 		}
 		
 		{{.Doc}}
-		func {{.Function}}(ctx context.Context) ({{.Return}}) {
+		func {{.Function}}({{.Signature}}) ({{.Return}}) {
 			{{.Vars}}
 			m := new(_bubbletea{{.Function}})
 			{{.Body}}
@@ -182,8 +182,10 @@ func (d driver) Template() string {
 func (d *driver) VisitArgument(a gofire.Argument) error {
 	_ = d.Driver.VisitArgument(a)
 	p := d.Last()
-	tp, ok := p.Type.(gofire.TPrimitive)
-	if !ok {
+	tp := p.Type
+	switch tp.(type) {
+	case gofire.TPrimitive, gofire.TText:
+	default:
 		return fmt.Errorf(
 			"driver %s: non primitive argument types are not supported, got an argument %s %s",
 			d.Name(),
@@ -191,9 +193,25 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 			a.Type.Type(),
 		)
 	}
-	if err := d.argument(p.Name, a.Index, tp); err != nil {
+	offset := d.postParse.Len()
+	if err := d.argument(p.Name, internal.Placeholder(a), a.Index, tp); err != nil {
 		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
 	}
+	// Optional arguments inputs could be left empty, so they fall back to their defaults.
+	internal.Fallback(
+		&d.postParse,
+		offset,
+		fmt.Sprintf("len(m.inputs) > %d && m.inputs[%d].Value() != \"\"", a.Index, a.Index),
+		p.Name,
+		tp,
+		a,
+	)
+	// Only required arguments inputs are marked, optional arguments inputs show their defaults instead.
+	doc := internal.ArgumentDoc(a, tp)
+	if !a.Optional {
+		doc += " (required)"
+	}
+	d.inputList = append(d.inputList, doc)
 	return nil
 }
 
@@ -201,25 +219,26 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	return fmt.Errorf("driver %s: doesn't support flags", d.Name())
 }
 
-func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error {
+func (d *driver) argument(name, label string, index uint64, t gofire.Typ) error {
 	k := t.Kind()
 	switch k {
 	case gofire.Bool:
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{	
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseBool(m.inputs[i].Value())
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = v
 				}
 			`,
 			index,
+			label,
 			name,
 		); err != nil {
 			return err
@@ -228,18 +247,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseInt(m.inputs[i].Value(), 10, %d)
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = %s(v)
 				}
 			`,
 			index,
+			label,
 			k.Base(),
 			name,
 			k.Type(),
@@ -250,18 +270,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseUint(m.inputs[i].Value(), 10, %d)
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = %s(v)
 				}
 			`,
 			index,
+			label,
 			k.Base(),
 			name,
 			k.Type(),
@@ -272,18 +293,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseFloat(m.inputs[i].Value(), %d)
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = %s(v)
 				}
 			`,
 			index,
+			label,
 			k.Base(),
 			name,
 			k.Type(),
@@ -294,18 +316,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseComplex(m.inputs[i].Value(), %d)
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = %s(v)
 				}
 			`,
 			index,
+			label,
 			k.Base(),
 			name,
 			k.Type(),
@@ -316,14 +339,38 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 		if _, err := fmt.Fprintf(&d.postParse,
 			`
 				{
-					const i = %d
+					const i, arg = %d, %q
 					if len(m.inputs) <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					%s = m.inputs[i].Value()
 				}
 			`,
 			index,
+			label,
+			name,
+		); err != nil {
+			return err
+		}
+	case gofire.Text:
+		if _, err := fmt.Fprintf(&d.postParse,
+			`
+				{
+					const i, arg = %d, %q
+					if len(m.inputs) <= i {
+						return fmt.Errorf("argument %%s is required", arg)
+					}
+					var v %s
+					if err := %s; err != nil {
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+					}
+					%s = v
+				}
+			`,
+			index,
+			label,
+			t.Type(),
+			internal.Unmarshal(t.(gofire.TText), "v", "m.inputs[i].Value()"),
 			name,
 		); err != nil {
 			return err
@@ -335,6 +382,5 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive) error
 			name,
 		)
 	}
-	d.inputList = append(d.inputList, fmt.Sprintf("arg [%d] %s", index, t.Type()))
 	return nil
 }
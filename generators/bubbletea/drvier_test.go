@@ -1,14 +1,17 @@
 package bubbletea
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/1pkg/gofire"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -63,10 +66,32 @@ func TestBubbleTeaDriver(t *testing.T) {
 	for tname, tcase := range table {
 		t.Run(tname, func(t *testing.T) {
 			exec := internal.GoExec("build -tags=tcases .")
-			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameBubbleTea, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.function)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameBubbleTea, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function})
 			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
 				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
 			}
 		})
 	}
 }
+
+func TestBubbleTeaDriverRequiredInputs(t *testing.T) {
+	cmd := gofire.Command{
+		Package:  "main",
+		Function: "echo",
+		Parameters: []gofire.Parameter{
+			gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int}},
+			gofire.Argument{Index: 1, Name: "b", Optional: true, Default: int64(10), Type: gofire.TPrimitive{TKind: gofire.Int}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := generators.Generate(context.TODO(), generators.DriverNameBubbleTea, cmd, &buf); err != nil {
+		t.Fatalf("generate should not fail on valid command %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "A int (required)") {
+		t.Fatalf("required argument input should be marked as required, got %q", out)
+	}
+	if !strings.Contains(out, "B int (default 10)\"") {
+		t.Fatalf("optional argument input should not be marked as required, got %q", out)
+	}
+}
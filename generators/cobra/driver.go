@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/config"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -20,8 +21,15 @@ type driver struct {
 	preParse   bytes.Buffer
 	postParse  bytes.Buffer
 	usageList  []string
+	argList    []string
+	argDocs    []string
 	nargs      uint
 	shortNames map[string]bool
+	repeats    map[string]bool
+	envs       map[string]string
+	xor        map[string][]string
+	with       map[string][]string
+	implies    map[string]string
 }
 
 func (d driver) Output(cmd gofire.Command) (string, error) {
@@ -29,14 +37,70 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 	if _, err := buf.Write(d.preParse.Bytes()); err != nil {
 		return "", err
 	}
+	// Flags groups are marked after all their flags are registered.
+	for _, group := range groups(d.xor) {
+		if _, err := fmt.Fprintf(&buf, "cli.MarkFlagsMutuallyExclusive(%s);", group); err != nil {
+			return "", err
+		}
+	}
+	for _, group := range groups(d.with) {
+		if _, err := fmt.Fprintf(&buf, "cli.MarkFlagsRequiredTogether(%s);", group); err != nil {
+			return "", err
+		}
+	}
+	var cfg string
+	if cmd.Config {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				var _config string
+				cli.Flags().StringVarP(&_config, %q, "", "", %q)
+				var _printConfig bool
+				cli.Flags().BoolVarP(&_printConfig, %q, "", false, %q)
+			`,
+			config.Flag,
+			internal.ConfigDoc,
+			config.PrintFlag,
+			internal.PrintConfigDoc,
+		); err != nil {
+			return "", err
+		}
+		d.usageList = append(d.usageList, fmt.Sprintf("--%s=%q", config.Flag, ""), fmt.Sprintf("--%s=false", config.PrintFlag))
+		cfg = internal.Config(
+			"func(name string) bool { return cli.Flags().Lookup(name) != nil }",
+			"cli.Flags().Changed",
+			"cli.Flags().Set",
+			`
+				cli.Flags().VisitAll(func(f *pflag.Flag) {
+					// slice flags are printed the same way they are loaded, as comma separated values.
+					if sv, ok := f.Value.(pflag.SliceValue); ok {
+						values[f.Name] = gofireconfig.Join(sv.GetSlice())
+						return
+					}
+					values[f.Name] = f.Value.String()
+				})
+			`,
+		)
+	}
+	// Environment and configuration values are resolved before cobra validates required flags.
 	if _, err := fmt.Fprintf(
 		&buf,
 		`
+			resolve = func() (err error) {
+				%s
+				%s
+				return
+			}
 			parse = func(ctx context.Context) (err error) {
+				%s
 				%s
 				return
 			}
 		`,
+		internal.Envs(d.envs, "cli.Flags().Changed", "cli.Flags().Set"),
+		cfg,
+		// Cobra has no implied flags rule, so they are checked along with the flags values.
+		internal.Implies(d.implies, "cli.Flags().Changed"),
 		d.postParse.String(),
 	); err != nil {
 		return "", err
@@ -44,21 +108,62 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 	if _, err := fmt.Fprintf(&buf, "cli.Args = cobra.MinimumNArgs(%d);", d.nargs); err != nil {
 		return "", err
 	}
-	digest := cmd.Function
+	digest := cmd.Title()
 	if len(cmd.Doc) > len(digest) {
 		digest += ": " + strings.Split(cmd.Doc, "\n")[0]
 	}
 	sort.Strings(d.usageList)
-	u := strings.Join(d.usageList, " ")
+	// Arguments are kept in their positional order after the flags.
+	u := strings.Join(append(d.usageList, d.argList...), " ")
 	if _, err := fmt.Fprintf(&buf, "cli.Short = %q;", digest); err != nil {
 		return "", err
 	}
-	if _, err := fmt.Fprintf(&buf, "cli.Use = %q;", cmd.Function+" "+u); err != nil {
+	if _, err := fmt.Fprintf(&buf, "cli.Use = %q;", cmd.Title()+" "+u); err != nil {
 		return "", err
 	}
-	if _, err := buf.WriteString("err = cli.ExecuteContext(ctx)"); err != nil {
+	long := cmd.Long
+	if len(d.argDocs) > 0 {
+		if long == "" {
+			long = cmd.Doc
+		}
+		// Cobra has no arguments help section, so the arguments are described in the long description.
+		// Note that tabs are used for alignment here as spaces are collapsed by generator.
+		long = strings.TrimSpace(long + "\n\nArguments:\n\t" + strings.Join(d.argDocs, "\n\t"))
+	}
+	if long != "" {
+		if _, err := fmt.Fprintf(&buf, "cli.Long = %q;", long); err != nil {
+			return "", err
+		}
+	}
+	if len(cmd.Aliases) > 0 {
+		if _, err := fmt.Fprintf(&buf, "cli.Aliases = %#v;", cmd.Aliases); err != nil {
+			return "", err
+		}
+	}
+	if len(cmd.Examples) > 0 {
+		// Note that tabs are used for alignment here as spaces are collapsed by generator.
+		if _, err := fmt.Fprintf(&buf, "cli.Example = %q;", "\t"+strings.Join(cmd.Examples, "\n\t")); err != nil {
+			return "", err
+		}
+	}
+	if cmd.Hidden {
+		if _, err := buf.WriteString("cli.Hidden = true;"); err != nil {
+			return "", err
+		}
+	}
+	if _, err := buf.WriteString("err = cli.ExecuteContext(ctx);"); err != nil {
 		return "", err
 	}
+	// Cobra handles the help flag without running the command, so the printed help is reported instead of the results.
+	if generators.Printed(cmd) {
+		if _, err := buf.WriteString(`
+			if err == nil && cli.Flags().Changed("help") {
+				err = gofireoutput.ErrPrinted
+			}
+		`); err != nil {
+			return "", err
+		}
+	}
 	return buf.String(), nil
 }
 
@@ -67,20 +172,51 @@ func (d *driver) Reset() error {
 	d.preParse.Reset()
 	d.postParse.Reset()
 	d.usageList = nil
+	d.argList = nil
+	d.argDocs = nil
 	d.shortNames = make(map[string]bool)
+	d.repeats = make(map[string]bool)
+	d.envs = make(map[string]string)
+	d.xor = make(map[string][]string)
+	d.with = make(map[string][]string)
+	d.implies = make(map[string]string)
 	d.nargs = 0
 	return nil
 }
 
+// groups returns the flags groups quoted flags names lists ordered by the groups names.
+func groups(groups map[string][]string) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lists := make([]string, 0, len(groups))
+	for _, name := range names {
+		flags := make([]string, 0, len(groups[name]))
+		for _, flag := range groups[name] {
+			flags = append(flags, fmt.Sprintf("%q", flag))
+		}
+		lists = append(lists, strings.Join(flags, ", "))
+	}
+	return lists
+}
+
 func (driver) Name() generators.DriverName {
 	return generators.DriverNameCobra
 }
 
 func (d driver) Imports() []string {
 	return []string{
+		`"errors"`,
 		`"fmt"`,
+		`"os"`,
 		`"strconv"`,
+		`"strings"`,
+		`"time"`,
 		`"github.com/spf13/cobra"`,
+		`"github.com/spf13/pflag"`,
+		`gofireconfig "github.com/1pkg/gofire/config"`,
 	}
 }
 
@@ -93,11 +229,23 @@ func (d driver) Template() string {
 		)
 		
 		{{.Doc}}
-		func {{.Function}}(ctx context.Context) ({{.Return}}) {
+		func {{.Function}}({{.Signature}}) ({{.Return}}) {
 			{{.Vars}}
 			var cli *cobra.Command
+			var resolve func() error
 			var parse func(context.Context) error
 			cli = &cobra.Command{
+				PreRunE: func(cmd *cobra.Command, _ []string) (err error) {
+					{{ if .Config }}
+						// printed output is not a failure, so neither the error nor the usage is reported.
+						if err = resolve(); errors.Is(err, gofireoutput.ErrPrinted) {
+							cmd.SilenceErrors, cmd.SilenceUsage = true, true
+						}
+						return
+					{{ else }}
+						return resolve()
+					{{ end }}
+				},
 				RunE: func(cmd *cobra.Command, _ []string) (err error) {
 					ctx := cmd.Context()
 					if err = parse(ctx); err != nil {
@@ -118,9 +266,8 @@ func (d driver) Template() string {
 func (d *driver) VisitArgument(a gofire.Argument) error {
 	_ = d.Driver.VisitArgument(a)
 	p := d.Last()
-	typ := a.Type
-	tp, ok := typ.(gofire.TPrimitive)
-	if !ok {
+	typ, _ := internal.Underlying(a.Type)
+	if !internal.Scalar(typ) {
 		return fmt.Errorf(
 			"driver %s: non primitive argument types are not supported, got an argument %s %s",
 			d.Name(),
@@ -128,13 +275,22 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 			typ.Type(),
 		)
 	}
-	if err := d.argument(p.Name, a.Index, tp, a.Ellipsis); err != nil {
+	offset := d.postParse.Len()
+	if err := d.argument(p.Name, internal.Placeholder(a), a.Index, typ, a.Ellipsis); err != nil {
 		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
 	}
+	internal.Fallback(&d.postParse, offset, fmt.Sprintf("cli.Flags().NArg() > %d", a.Index), p.Name, typ, a)
+	// Optional arguments are not counted towards the minimum number of arguments.
+	if a.Optional {
+		d.nargs--
+	}
+	d.argList = append(d.argList, internal.ArgumentUsage(a, typ))
+	d.argDocs = append(d.argDocs, internal.ArgumentDoc(a, typ))
 	return nil
 }
 
 func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
+	f.Implies = internal.Implied(f, g)
 	_ = d.Driver.VisitFlag(f, g)
 	p := d.Last()
 	typ := p.Type
@@ -150,34 +306,111 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	case 0:
 	case 1:
 		if d.shortNames[p.Short] {
-			return fmt.Errorf("driver %s: short flag name %q has been already registred", d.Name(), p.Short)
+			return fmt.Errorf("driver %s: short flag name %q has been already registered", d.Name(), p.Short)
 		}
 		d.shortNames[p.Short] = true
 	default:
 		return fmt.Errorf("driver %s: short flag name %q is not supported", d.Name(), p.Short)
 	}
-	if err := d.flag(p.Name, full, p.Short, typ, ptr, f.Default, f.Doc, f.Deprecated, f.Hidden); err != nil {
+	if p.Repeatable {
+		if p.Short != "" {
+			return fmt.Errorf("driver %s: short flag name %q is not supported for repeatable groups", d.Name(), p.Short)
+		}
+		if p.Required {
+			return fmt.Errorf("driver %s: required flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if len(f.Enum) > 0 {
+			return fmt.Errorf("driver %s: enum flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if internal.Constrained(f) {
+			return fmt.Errorf("driver %s: constrained flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if f.Xor != "" || f.With != "" || f.Implies != "" {
+			return fmt.Errorf("driver %s: xor, with and implies flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if f.Optional {
+			return fmt.Errorf("driver %s: optional flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if err := d.repeatable(p.Name, p.Ref.Group(), p.Full, typ, ptr, f.Default, f.Doc, f.Deprecated, f.Hidden); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+		return nil
+	}
+	offset := d.preParse.Len()
+	if err := d.flag(p.Name, full, p.Short, typ, ptr, f.Default, internal.Doc(f.Doc, f), f.Deprecated, f.Hidden); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	// Persistent group flags are registered as persistent flags, so they are shared with subcommands.
+	persistent := g != nil && g.Persistent
+	if persistent {
+		code := bytes.ReplaceAll(d.preParse.Bytes()[offset:], []byte("cli.Flags()."), []byte("cli.PersistentFlags()."))
+		d.preParse.Truncate(offset)
+		if _, err := d.preParse.Write(code); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+	}
+	changed := fmt.Sprintf("cli.Flags().Changed(%q)", full)
+	if _, err := d.postParse.WriteString(internal.Enum(full, p.Name+"_", changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.postParse.WriteString(internal.Constraints(full, p.Name+"_", changed, f)); err != nil {
 		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
 	}
+	if _, err := d.postParse.WriteString(internal.Optional(p.Name, changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if p.Env != "" {
+		d.envs[full] = p.Env
+	}
+	if p.Required {
+		mark := "MarkFlagRequired"
+		if persistent {
+			mark = "MarkPersistentFlagRequired"
+		}
+		if _, err := fmt.Fprintf(&d.preParse, "_ = cli.%s(%q);", mark, full); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+	}
+	if f.Xor != "" {
+		d.xor[f.Xor] = append(d.xor[f.Xor], full)
+	}
+	if f.With != "" {
+		d.with[f.With] = append(d.with[f.With], full)
+	}
+	if f.Implies != "" {
+		d.implies[full] = f.Implies
+	}
+	// Flags with allowed values are completed with them.
+	if choices := f.Choices(); len(choices) > 0 {
+		if _, err := fmt.Fprintf(
+			&d.preParse,
+			"_ = cli.RegisterFlagCompletionFunc(%q, cobra.FixedCompletions(%#v, cobra.ShellCompDirectiveNoFileComp));",
+			full,
+			choices,
+		); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+	}
 	return nil
 }
 
-func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellipsis bool) error {
+func (d *driver) argument(name, label string, index uint64, t gofire.Typ, ellipsis bool) error {
 	k := t.Kind()
 	if ellipsis {
 		switch k {
 		case gofire.Bool:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < cli.Flags().NArg(); i++ {
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
 						v, err := strconv.ParseBool(cli.Flags().Arg(i))
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, v)
 					}
 				`,
 				index,
+				label,
 				name,
 				name,
 			); err != nil {
@@ -186,15 +419,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < cli.Flags().NArg(); i++ {
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
 						v, err := strconv.ParseInt(cli.Flags().Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -205,15 +439,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < cli.Flags().NArg(); i++ {
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
 						v, err := strconv.ParseUint(cli.Flags().Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -224,15 +459,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Float32, gofire.Float64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < cli.Flags().NArg(); i++ {
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
 						v, err := strconv.ParseFloat(cli.Flags().Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -253,6 +489,63 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			); err != nil {
 				return err
 			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
+						v, err := time.ParseDuration(cli.Flags().Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
+						v, err := time.Parse(%q, cli.Flags().Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < cli.Flags().NArg(); i++ {
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "cli.Flags().Arg(i)"),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf(
 				"ellipsis type %s is not supported for an argument %s",
@@ -266,18 +559,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 				{	
-					const i = %d
+					const i, arg = %d, %q
 					if cli.Flags().NArg() <= i {
-						return fmt.Errorf("argument %%d-th is required", i)
+						return fmt.Errorf("argument %%s is required", arg)
 					}
 					v, err := strconv.ParseBool(cli.Flags().Arg(i))
 					if err != nil {
-						return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+						return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 					}
 					%s = v
 				}
 			`,
 				index,
+				label,
 				name,
 			); err != nil {
 				return err
@@ -286,18 +580,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if cli.Flags().NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseInt(cli.Flags().Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -308,18 +603,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if cli.Flags().NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseUint(cli.Flags().Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -330,18 +626,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if cli.Flags().NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseFloat(cli.Flags().Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -352,14 +649,81 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if cli.Flags().NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						%s = cli.Flags().Arg(i)
 					}
 				`,
 				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if cli.Flags().NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.ParseDuration(cli.Flags().Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if cli.Flags().NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.Parse(%q, cli.Flags().Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if cli.Flags().NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "cli.Flags().Arg(i)"),
 				name,
 			); err != nil {
 				return err
@@ -373,7 +737,6 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		}
 	}
 	d.nargs++
-	d.usageList = append(d.usageList, fmt.Sprintf("arg%d", index))
 	return nil
 }
 
@@ -393,6 +756,8 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 			fallthrough
 		case gofire.Float32, gofire.Float64:
 			fallthrough
+		case gofire.Duration:
+			fallthrough
 		case gofire.String:
 			if _, err := fmt.Fprintf(&d.preParse,
 				`
@@ -401,7 +766,7 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 				`,
 				name,
 				ts.Type(),
-				strings.Title(etyp.Type()),
+				internal.Verb(etyp.Type()),
 				name,
 				full,
 				short,
@@ -436,6 +801,8 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 		fallthrough
 	case gofire.Float32, gofire.Float64:
 		fallthrough
+	case gofire.Duration:
+		fallthrough
 	case gofire.String:
 		if _, err := fmt.Fprintf(&d.preParse,
 			`
@@ -444,7 +811,7 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 			`,
 			name,
 			t.Type(),
-			strings.Title(t.Type()),
+			internal.Verb(t.Type()),
 			name,
 			full,
 			short,
@@ -464,6 +831,86 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 		); err != nil {
 			return err
 		}
+	case gofire.Time:
+		// Time flags are parsed from their string representation accordingly to the layout.
+		tt := t.(gofire.TTime)
+		if _, err := fmt.Fprintf(&d.preParse,
+			`
+				var %s_ string
+				cli.Flags().StringVarP(&%s_, %q, %q, %s, %q)
+			`,
+			name,
+			name,
+			full,
+			short,
+			internal.TimeDefault(tt, val),
+			doc,
+		); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(
+			&d.postParse,
+			`
+				{
+					var v time.Time
+					if %s_ != "" {
+						t, err := time.Parse(%q, %s_)
+						if err != nil {
+							return fmt.Errorf("flag %s parse error: %%v", err)
+						}
+						v = t
+					}
+					%s = %sv
+				}
+			`,
+			name,
+			tt.TimeLayout(),
+			name,
+			full,
+			name,
+			amp,
+		); err != nil {
+			return err
+		}
+	case gofire.Text:
+		// Text flags are parsed from their raw string representation through the type interface.
+		tt := t.(gofire.TText)
+		if _, err := fmt.Fprintf(&d.preParse,
+			`
+				var %s_ string
+				cli.Flags().StringVarP(&%s_, %q, %q, %s, %q)
+			`,
+			name,
+			name,
+			full,
+			short,
+			tt.Format(val),
+			doc,
+		); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(
+			&d.postParse,
+			`
+				{
+					var v %s
+					if %s_ != "" {
+						if err := %s; err != nil {
+							return fmt.Errorf("flag %s parse error: %%v", err)
+						}
+					}
+					%s = %sv
+				}
+			`,
+			tt.Type(),
+			name,
+			internal.Unmarshal(tt, "v", name+"_"),
+			full,
+			name,
+			amp,
+		); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf(
 			"type %s is not supported for a flag %s",
@@ -504,10 +951,95 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 		}
 		return nil
 	}
-	u := fmt.Sprintf("--%s=%s", full, t.Format(val))
+	u := fmt.Sprintf("--%s=%s", full, internal.Usage(t, val))
 	if short != "" {
-		u += " " + fmt.Sprintf("-%s=%s", short, t.Format(val))
+		u += " " + fmt.Sprintf("-%s=%s", short, internal.Usage(t, val))
 	}
 	d.usageList = append(d.usageList, u)
 	return nil
 }
+
+// repeatable registers the flag for every repeatable group index provided in the command line,
+// flags are named after the group, the index and the field e.g. --servers.0.host.
+func (d *driver) repeatable(name, group, field string, t gofire.Typ, ptr bool, val interface{}, doc string, deprecated, hidden bool) error {
+	full := fmt.Sprintf("%s.N.%s", group, field)
+	var verb string
+	switch t.Kind() {
+	case gofire.Slice:
+		etyp := t.(gofire.TSlice).ETyp
+		switch etyp.Kind() {
+		case gofire.Bool, gofire.Int32, gofire.Int64, gofire.Float32, gofire.Float64, gofire.Duration, gofire.String:
+			verb = internal.Verb(etyp.Type()) + "Slice"
+		default:
+			return fmt.Errorf(
+				"type %s is not supported for a flag %s",
+				t.Type(),
+				full,
+			)
+		}
+	case gofire.Bool:
+		fallthrough
+	case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
+		fallthrough
+	case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
+		fallthrough
+	case gofire.Float32, gofire.Float64:
+		fallthrough
+	case gofire.Duration:
+		fallthrough
+	case gofire.String:
+		verb = internal.Verb(t.Type())
+	default:
+		return fmt.Errorf(
+			"type %s is not supported for a flag %s",
+			t.Type(),
+			full,
+		)
+	}
+	// Count the group indexes only once for all the group flags.
+	if !d.repeats[group] {
+		if _, err := d.preParse.WriteString(internal.Repeats(group, "_, arg := range os.Args[1:]")); err != nil {
+			return err
+		}
+		d.repeats[group] = true
+	}
+	typ, ref, alloc := t.Type(), fmt.Sprintf("&%s[i]", name), ""
+	if ptr {
+		typ, ref, alloc = "*"+t.Type(), fmt.Sprintf("%s[i]", name), fmt.Sprintf("%s[i] = new(%s)", name, t.Type())
+	}
+	var marks string
+	if deprecated {
+		marks += fmt.Sprintf(`cli.Flags().MarkDeprecated(full, "deprecated: %s")`+"\n", doc)
+	}
+	if hidden {
+		marks += `cli.Flags().MarkHidden(full)`
+	}
+	if _, err := fmt.Fprintf(&d.preParse,
+		`
+			%[1]s = make([]%[2]s, g%[3]s_n)
+			for i := range %[1]s {
+				full := fmt.Sprintf("%[3]s.%%d.%[4]s", i)
+				%[5]s
+				cli.Flags().%[6]sVarP(%[7]s, full, "", %[8]s, %[9]q)
+				%[10]s
+			}
+		`,
+		name,
+		typ,
+		group,
+		field,
+		alloc,
+		verb,
+		ref,
+		t.Format(val),
+		doc,
+		marks,
+	); err != nil {
+		return err
+	}
+	if hidden {
+		return nil
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, internal.Usage(t, val)))
+	return nil
+}
@@ -1,6 +1,7 @@
 package cobra
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -8,8 +9,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/cmd"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -37,15 +41,16 @@ func TestCobraDriver(t *testing.T) {
 			pckg:     "main",
 			function: "echo",
 			params:   []string{"--a=test", "--b", "100", "--c", "10", "--d=true", "--e", "10.125", "test1", "101", "11", "false", "10.5"},
-			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:10.500\n",
+			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:10.500\n0\n",
 		},
 		"echo primitive params types should produce expected output on help flag": {
 			dir:      "echo_primitive_params",
 			pckg:     "main",
 			function: "echo",
 			params:   []string{"--help"},
-			out: `echo: echo documentation string.
-Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4
+			out: `echo documentation string.
+Arguments: A1 string B1 int C1 uint64 D1 bool E1 float32
+Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1
 Flags: --a string --b int --c uint --d --e float32 -h, --help help for echo
 `,
 		},
@@ -56,7 +61,7 @@ Flags: --a string --b int --c uint --d --e float32 -h, --help help for echo
 			params:   []string{"--e", "test"},
 			err:      errors.New("exit status 1"),
 			out: `Error: invalid argument "test" for "--e" flag: strconv.ParseFloat: parsing "test": invalid syntax
-Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4
+Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1
 Flags: --a string --b int --c uint --d --e float32 -h, --help help for echo
 invalid argument "test" for "--e" flag: strconv.ParseFloat: parsing "test": invalid syntax
 exit status 2
@@ -69,7 +74,7 @@ exit status 2
 			params:   []string{"--a=test", "--b", "100", "--c", "10", "--d=true", "--e", "10.125", "test1"},
 			err:      errors.New("exit status 1"),
 			out: `Error: requires at least 5 arg(s), only received 1
-Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4
+Usage: echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1
 Flags: --a string --b int --c uint --d --e float32 -h, --help help for echo
 requires at least 5 arg(s), only received 1
 exit status 2
@@ -125,12 +130,53 @@ exit status 2
 1:100 2:test 3:[10.5 10]
 `,
 		},
+		"echo repeatable group params should produce expected output on valid params": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.0.host=a", "--servers.0.port=1", "--servers.1.host=b"},
+			out:      "a:1\nb:80\n",
+		},
+		"echo repeatable group params should produce expected error on out of range index": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.999999999999.host=a"},
+			err:      errors.New("exit status 1"),
+			out: `flag servers.999999999999.host index 999999999999 is out of range
+exit status 2
+`,
+		},
+		"echo repeatable group params should produce expected error on indexes gap": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.0.host=a", "--servers.2.host=b"},
+			err:      errors.New("exit status 1"),
+			out: `group servers indexes have to be provided continuously from 0 to 2
+exit status 2
+`,
+		},
+		"echo time params should produce expected output on valid params": {
+			dir:      "echo_time_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--timeout=1.5s", "--opt.Until=2022-03-04", "2021-02-03T04:05:06Z"},
+			out:      "1.5s 2021-02-03T04:05:06Z 30s 2022-03-04\n",
+		},
+		"echo text params should produce expected output on valid params": {
+			dir:      "echo_text_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--lvl=debug", "10.0.0.1"},
+			out:      "debug 10.0.0.1 127.0.0.1\n",
+		},
 		"echo group params with invalid tags fail on driver generation": {
 			dir:      "echo_group_params_tags_invalid",
 			pckg:     "main",
 			function: "echo",
 			params:   []string{"-b=test", "--g1.flag1=100"},
-			err:      errors.New(`driver cobra: short flag name "a" has been already registred`),
+			err:      errors.New(`driver cobra: short flag name "a" has been already registered`),
 		},
 		"echo ellipsis params types should produce expected output on valid params": {
 			dir:      "echo_ellipsis_params",
@@ -139,11 +185,639 @@ exit status 2
 			params:   []string{"1", "10", "100"},
 			out:      "[1 10 100]\n",
 		},
+		"echo required params should produce expected output on valid params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--conn.Host=localhost", "--conn.User=admin"},
+			out:      "localhost admin 5432\n",
+		},
+		"echo required params should produce expected error on missing params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--conn.Port=6000"},
+			err:      errors.New("exit status 1"),
+			out: `Error: required flag(s) "conn.Host", "conn.User" not set
+Usage:
+  echo --conn.Host="" --conn.Port=5432 --conn.User=""
+
+Flags:
+      --conn.Host string   database host. (required)
+      --conn.Port int      database port. (default 5432)
+      --conn.User string   database user. (required)
+  -h, --help               help for echo
+
+required flag(s) "conn.Host", "conn.User" not set
+exit status 2
+`,
+		},
+		"echo enum params should produce expected output on valid params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Format=json", "--log.Verbosity=2", "--log.Level=warn", "debug"},
+			out:      "json 2 2 0\n",
+		},
+		"echo enum params should produce expected output on constants literal values": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Level=2", "0"},
+			out:      "text 0 2 0\n",
+		},
+		"echo enum params should produce expected output on default params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"info"},
+			out:      "text 0 1 1\n",
+		},
+		"echo enum params should produce expected error on invalid enum flags": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Format=xml", "info"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag log.Format value xml is not one of text, json
+Usage:
+  echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL
+
+Flags:
+  -h, --help                help for echo
+      --log.Format string   log format. (one of text, json) (default "text")
+      --log.Level string    log level. (one of debug, info, warn) (default "info")
+      --log.Verbosity int   log verbosity. (one of 1, 2, 3)
+
+flag log.Format value xml is not one of text, json
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on provided default value outside of enum": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Verbosity=0", "info"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag log.Verbosity value 0 is not one of 1, 2, 3
+Usage:
+  echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL
+
+Flags:
+  -h, --help                help for echo
+      --log.Format string   log format. (one of text, json) (default "text")
+      --log.Level string    log level. (one of debug, info, warn) (default "info")
+      --log.Verbosity int   log verbosity. (one of 1, 2, 3)
+
+flag log.Verbosity value 0 is not one of 1, 2, 3
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on invalid constant names": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Level=error", "info"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag log.Level parse error: error is not one of debug, info, warn
+Usage:
+  echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL
+
+Flags:
+  -h, --help                help for echo
+      --log.Format string   log format. (one of text, json) (default "text")
+      --log.Level string    log level. (one of debug, info, warn) (default "info")
+      --log.Verbosity int   log verbosity. (one of 1, 2, 3)
+
+flag log.Level parse error: error is not one of debug, info, warn
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected output on valid params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Host=api.local", "--srv.Port=443", "--srv.Name=web", "--srv.Tags=a,b"},
+			out:      "api.local 443 web [a b]\n",
+		},
+		"echo constrained params should produce expected output on default params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "localhost 8080  []\n",
+		},
+		"echo constrained params should produce expected error on out of range flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Port=70000"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag srv.Port value 70000 is greater than max 65535
+Usage:
+  echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{}
+
+Flags:
+  -h, --help               help for echo
+      --srv.Host string    server host. (default "localhost")
+      --srv.Name string    server name.
+      --srv.Port int       server port. (default 8080)
+      --srv.Tags strings   server tags.
+
+flag srv.Port value 70000 is greater than max 65535
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on not matching pattern flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Host=API"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag srv.Host value "API" doesn't match pattern ^[a-z.]+$
+Usage:
+  echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{}
+
+Flags:
+  -h, --help               help for echo
+      --srv.Host string    server host. (default "localhost")
+      --srv.Name string    server name.
+      --srv.Port int       server port. (default 8080)
+      --srv.Tags strings   server tags.
+
+flag srv.Host value "API" doesn't match pattern ^[a-z.]+$
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on provided default value outside of constraints": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Name="},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag srv.Name value length 0 is less than minlen 2
+Usage:
+  echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{}
+
+Flags:
+  -h, --help               help for echo
+      --srv.Host string    server host. (default "localhost")
+      --srv.Name string    server name.
+      --srv.Port int       server port. (default 8080)
+      --srv.Tags strings   server tags.
+
+flag srv.Name value length 0 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on too short flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Name=w"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag srv.Name value length 1 is less than minlen 2
+Usage:
+  echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{}
+
+Flags:
+  -h, --help               help for echo
+      --srv.Host string    server host. (default "localhost")
+      --srv.Name string    server name.
+      --srv.Port int       server port. (default 8080)
+      --srv.Tags strings   server tags.
+
+flag srv.Name value length 1 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo implied params should produce expected output on valid params": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=cert.pem", "--sec.Key=key.pem"},
+			out:      "cert.pem key.pem\n",
+		},
+		"echo implied params should produce expected output on implied flag only": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Key=key.pem"},
+			out:      " key.pem\n",
+		},
+		"echo implied params should produce expected error on missing implied flag": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=cert.pem"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag sec.Cert requires flag sec.Key to be provided
+Usage:
+  echo --sec.Cert="" --sec.Key=""
+
+Flags:
+  -h, --help              help for echo
+      --sec.Cert string   tls certificate path. (implies sec.Key)
+      --sec.Key string    tls key path.
+
+flag sec.Cert requires flag sec.Key to be provided
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on too long list flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Tags=a,b,c"},
+			err:      errors.New("exit status 1"),
+			out: `Error: flag srv.Tags value length 3 is greater than maxlen 2
+Usage:
+  echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{}
+
+Flags:
+  -h, --help               help for echo
+      --srv.Host string    server host. (default "localhost")
+      --srv.Name string    server name.
+      --srv.Port int       server port. (default 8080)
+      --srv.Tags strings   server tags.
+
+flag srv.Tags value length 3 is greater than maxlen 2
+exit status 2
+`,
+		},
+		"echo related params should produce expected output on valid params": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--src.File=in.txt", "--sec.Cert=c.pem", "--sec.Key=k.pem"},
+			out:      "in.txt  c.pem k.pem\n",
+		},
+		"echo related params should produce expected error on mutually exclusive flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--src.File=in.txt", "--src.URL=http://in"},
+			err:      errors.New("exit status 1"),
+			out: `Error: if any flags in the group [src.File src.URL] are set none of the others can be; [src.File src.URL] were all set
+Usage:
+  echo --sec.Cert="" --sec.Key="" --src.File="" --src.URL=""
+
+Flags:
+  -h, --help              help for echo
+      --sec.Cert string   tls certificate path. (with tls)
+      --sec.Key string    tls key path. (with tls)
+      --src.File string   input file path. (xor input)
+      --src.URL string    input url. (xor input)
+
+if any flags in the group [src.File src.URL] are set none of the others can be; [src.File src.URL] were all set
+exit status 2
+`,
+		},
+		"echo related params should produce expected error on not together flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=c.pem"},
+			err:      errors.New("exit status 1"),
+			out: `Error: if any flags in the group [sec.Cert sec.Key] are set they must all be set; missing [sec.Key]
+Usage:
+  echo --sec.Cert="" --sec.Key="" --src.File="" --src.URL=""
+
+Flags:
+  -h, --help              help for echo
+      --sec.Cert string   tls certificate path. (with tls)
+      --sec.Key string    tls key path. (with tls)
+      --src.File string   input file path. (xor input)
+      --src.URL string    input url. (xor input)
+
+if any flags in the group [sec.Cert sec.Key] are set they must all be set; missing [sec.Key]
+exit status 2
+`,
+		},
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false 30 nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--lim.Retries=3"},
+			out:      "false 30 3\n",
+		},
+		"echo directive params should produce expected output on default params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "6\n",
+		},
+		"echo directive params should produce expected output on valid params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-m=3", "1", "2"},
+			out:      "9\n",
+		},
+		"echo directive params should produce expected error on missing argument": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--mult=3", "1"},
+			err:      errors.New("exit status 1"),
+			out: `Error: requires at least 2 arg(s), only received 1
+Usage:
+  echo --mult=2 -m=2 LEFT RIGHT
+
+Flags:
+  -h, --help       help for echo
+  -m, --mult int   result multiplier. (default 2)
+
+requires at least 2 arg(s), only received 1
+exit status 2
+`,
+		},
+		"echo named command should produce expected output on valid params": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"hello"},
+			out:      "hello\n",
+		},
+		"echo named command should produce expected error on missing argument": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			err:      errors.New("exit status 1"),
+			out: `Error: requires at least 1 arg(s), only received 0
+Usage:
+  echo-text TEXT
+
+Aliases:
+  echo-text, et
+
+Examples:
+	echo-text hello
+
+Flags:
+  -h, --help   help for echo-text
+
+requires at least 1 arg(s), only received 0
+exit status 2
+`,
+		},
+		"echo documented args should produce expected output on valid params": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "3\n",
+		},
+		"echo documented args should produce expected error on missing argument": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1"},
+			err:      errors.New("exit status 1"),
+			out: `Error: requires at least 2 arg(s), only received 1
+Usage:
+  echo LEFT RIGHT
+
+Flags:
+  -h, --help   help for echo
+
+requires at least 2 arg(s), only received 1
+exit status 2
+`,
+		},
+		"echo documented args should produce expected output on help flag": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--help"},
+			out: `echo documentation string.
+
+Arguments:
+	LEFT int left operand.
+	RIGHT int right operand.
+
+Usage:
+  echo LEFT RIGHT
+
+Flags:
+  -h, --help   help for echo
+`,
+		},
+		"echo optional args should produce expected output on omitted optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt"},
+			out:      "log.txt 10\n",
+		},
+		"echo optional args should produce expected output on provided optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt", "5"},
+			out:      "log.txt 5\n",
+		},
+		"echo optional args should produce expected error on missing argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			err:      errors.New("exit status 1"),
+			out: `Error: requires at least 1 arg(s), only received 0
+Usage:
+  tail FILE [LINES=10]
+
+Flags:
+  -h, --help   help for tail
+
+requires at least 1 arg(s), only received 0
+exit status 2
+`,
+		},
+		"echo output results should produce expected text output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"10"},
+			out:      "NAME   AGE\nalice  30\n",
+		},
+		"echo output results should produce expected json output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=json", "0"},
+			out: `[
+  {
+    "name": "alice",
+    "age": 30
+  },
+  {
+    "name": "bob",
+    "age": 7
+  }
+]
+`,
+		},
+		"echo output results should produce expected yaml output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"-output", "yaml", "0"},
+			out: `- name: alice
+  age: 30
+- name: bob
+  age: 7
+`,
+		},
+		"echo output results should produce expected error on error result": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"200"},
+			err:      errors.New("exit status 1"),
+			out: `age is out of range
+exit status 2
+`,
+		},
+		"echo output results should produce expected error on unsupported output format": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=xml", "0"},
+			err:      errors.New("exit status 1"),
+			out: `output format "xml" is not supported, one of text, json, yaml is expected
+exit status 2
+`,
+		},
+		"echo output results should not print silent command results": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "greet",
+			params:   []string{"bob"},
+			out:      "hello bob\n",
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function})
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if unify.ReplaceAllString(tcase.out, "") != unify.ReplaceAllString(out, "") {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestCobraDriverTree(t *testing.T) {
+	unify := regexp.MustCompile(`\s|\n|^\d+`)
+	table := map[string]struct {
+		dir       string
+		pckg      string
+		functions []string
+		params    []string
+		out       string
+		err       error
+	}{
+		"echo tree should produce expected output on valid command params": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"mul", "--factor=3", "10"},
+			out:       "30\n",
+		},
+		"echo tree should produce expected output on printed command results": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"add", "--output=json", "10", "20"},
+			out:       "30\n",
+		},
+		"echo tree should produce expected output on help flag": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"--help"},
+			out: `command [--help -h]
+commands:
+	add	add sums two numbers.
+	mul	mul multiplies two numbers
+`,
+		},
+		"echo tree should produce expected output on valid command help flag": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"add", "--help"},
+			out: `add sums two numbers.
+
+Arguments:
+	A int
+	B int
+
+Usage:
+  add A B
+
+Flags:
+  -h, --help   help for add
+`,
+		},
+		"echo methods tree should produce expected output on shared params before command": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"--c.addr", "remote", "--c.retries=3", "Fetch", "res"},
+			out:       "fetch res from remote with 3 retries\n",
+		},
+		"echo methods tree should produce expected output on shared params after command": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"Ping", "--c.addr=remote"},
+			out:       "ping remote\n",
+		},
+		"echo methods tree should produce expected output on help flag": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"Ping", "--help"},
+			out: `Ping: Ping pings the remote.
+
+Usage:
+	Ping --c.addr="localhost" --c.retries=0
+
+Flags:
+	--c.addr string	remote address. (default "localhost")
+	--c.retries int
+	-h, --help	help for Ping
+`,
+		},
+		"echo tree should produce expected error on unknown command": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"sub", "1", "2"},
+			err:       errors.New("exit status 1"),
+			out: `command [--help -h]
+commands:
+	add	add sums two numbers.
+	mul	mul multiplies two numbers
+command "sub" is unknown
+exit status 2
+`,
+		},
 	}
 	for tname, tcase := range table {
 		t.Run(tname, func(t *testing.T) {
 			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
-			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.function)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.functions)
 			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
 				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
 			}
@@ -153,3 +827,292 @@ exit status 2
 		})
 	}
 }
+
+func TestCobraDriverEnv(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		env      map[string]string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo env params should produce expected output on env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_VERBOSE": "true", "APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			out:      "true remote 6000\n",
+		},
+		"echo env params should prefer explicit flags over env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			params:   []string{"--conn.Host=local"},
+			out:      "false local 6000\n",
+		},
+		"echo env params should produce expected output on default params": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false localhost 5432\n",
+		},
+		"echo env params should produce expected error on invalid env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"DB_PORT": "port"},
+			err:      errors.New("exit status 1"),
+			out: `Error: env DB_PORT parse error: invalid argument "port" for "--conn.Port" flag: strconv.ParseInt: parsing "port": invalid syntax
+Usage:
+  echo --conn.Host="localhost" --conn.Port=5432 --verbose=false
+
+Flags:
+      --conn.Host string   database host. (env APP_CONN_HOST) (default "localhost")
+      --conn.Port int      database port. (env DB_PORT) (default 5432)
+  -h, --help               help for echo
+      --verbose             (env APP_VERBOSE)
+
+env DB_PORT parse error: invalid argument "port" for "--conn.Port" flag: strconv.ParseInt: parsing "port": invalid syntax
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			for k, v := range tcase.env {
+				t.Setenv(k, v)
+			}
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithEnvPrefix("APP"))
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestCobraDriverOptional(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "nil nil nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--verbose", "--lim.Retries=3"},
+			out:      "true nil 3\n",
+		},
+		"echo optional params should produce expected output on params set to defaults": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--verbose=false", "--lim.Timeout=30"},
+			out:      "false 30 nil\n",
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithOptional())
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestCobraDriverConfig(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		config   string
+		data     string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo config params should produce expected output on json config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"verbose": true, "tags": ["x", "y"], "db": {"Host": "remote", "Port": 6000}}`,
+			out:      "true [x y] remote 6000\n",
+		},
+		"echo config params should produce expected output on yaml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Host: remote\n",
+			out:      "false [] remote 5432\n",
+		},
+		"echo config params should produce expected output on toml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "verbose = true\n[db]\nPort = 6000\n",
+			out:      "true [] localhost 6000\n",
+		},
+		"echo config params should produce expected output on yaml config with quoted list items": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "tags: ['x,y', z]\n",
+			out:      "false [x,y z] localhost 5432\n",
+		},
+		"echo config params should produce expected output on print config flag with quoted list items": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "tags: ['x,y', z]\n",
+			params:   []string{"--print-config"},
+			out: `{
+  "db": {
+    "Host": "localhost",
+    "Port": "5432"
+  },
+  "tags": "\"x,y\",z",
+  "verbose": "false"
+}
+`,
+		},
+		"echo config params should prefer explicit flags over config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Host": "remote", "Port": 6000}}`,
+			params:   []string{"--db.Host=local"},
+			out:      "false [] local 6000\n",
+		},
+		"echo config params should produce expected output on print config flag": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Port: 6000\n",
+			params:   []string{"--verbose", "--tags=a,b", "--print-config"},
+			out: `{
+  "db": {
+    "Host": "localhost",
+    "Port": "6000"
+  },
+  "tags": "a,b",
+  "verbose": "true"
+}
+`,
+		},
+		"echo config params should produce expected error on unknown config keys": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Name": "test"}}`,
+			err:      errors.New("exit status 1"),
+			out: `Error: config key db.Name is unknown
+Usage:
+  echo --config="" --db.Host="localhost" --db.Port=5432 --print-config=false --tags=[]string{} --verbose=false
+
+Flags:
+      --config string    path to the configuration file (json, yaml or toml).
+      --db.Host string   database host. (default "localhost")
+      --db.Port int      database port. (default 5432)
+  -h, --help             help for echo
+      --print-config     print the effective configuration and exit.
+      --tags strings     
+      --verbose
+
+config key db.Name is unknown
+exit status 2
+`,
+		},
+		"echo config params should produce expected error on invalid config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "[db]\nPort = 'port'\n",
+			err:      errors.New("exit status 1"),
+			out: `Error: config key db.Port parse error: invalid argument "port" for "--db.Port" flag: strconv.ParseInt: parsing "port": invalid syntax
+Usage:
+  echo --config="" --db.Host="localhost" --db.Port=5432 --print-config=false --tags=[]string{} --verbose=false
+
+Flags:
+      --config string    path to the configuration file (json, yaml or toml).
+      --db.Host string   database host. (default "localhost")
+      --db.Port int      database port. (default 5432)
+  -h, --help             help for echo
+      --print-config     print the effective configuration and exit.
+      --tags strings     
+      --verbose
+
+config key db.Port parse error: invalid argument "port" for "--db.Port" flag: strconv.ParseInt: parsing "port": invalid syntax
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tcase.config)
+			if err := os.WriteFile(path, []byte(tcase.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			params := append([]string{"--config=" + path}, tcase.params...)
+			exec := internal.GoExec("run -tags=tcases .", params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameCobra, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithConfig())
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestCobraDriverHelp(t *testing.T) {
+	cmd := gofire.Command{
+		Package:  "echo",
+		Function: "echo",
+		Results:  []string{"int"},
+		Parameters: []gofire.Parameter{
+			gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := generators.Generate(context.TODO(), generators.DriverNameCobra, cmd, &buf); err != nil {
+		t.Fatalf("generate should not fail on valid command %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "err = gofireoutput.ErrPrinted") {
+		t.Fatalf("printed help should be reported to the caller, got %q", out)
+	}
+	if strings.Contains(out, "os.Exit") {
+		t.Fatalf("command should never exit on its own, got %q", out)
+	}
+}
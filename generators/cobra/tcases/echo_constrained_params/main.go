@@ -0,0 +1,23 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type server struct {
+	// server host.
+	Host string `gofire:"pattern='^[a-z.]+$',default=localhost"`
+	// server port.
+	Port int `gofire:"min=1,max=65535,default=8080"`
+	// server name.
+	Name string `gofire:"minlen=2,maxlen=8"`
+	// server tags.
+	Tags []string `gofire:"maxlen=2"`
+}
+
+// echo documentation string.
+func echo(srv server) {
+	fmt.Println(srv.Host, srv.Port, srv.Name, srv.Tags)
+}
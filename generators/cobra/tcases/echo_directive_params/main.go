@@ -0,0 +1,16 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+// echo documentation string.
+//
+//gofire:param mult short=m default=2 doc='result multiplier.'
+//gofire:arg a name=left doc='left operand.'
+//gofire:arg b name=right
+func echo(a int, b int, mult *int) {
+	fmt.Println((a + b) * *mult)
+}
@@ -0,0 +1,36 @@
+//go:build tcases
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+// users lists the users not younger than the age.
+func users(age int) ([]user, error) {
+	if age > 150 {
+		return nil, errors.New("age is out of range")
+	}
+	var list []user
+	for _, u := range []user{{Name: "alice", Age: 30}, {Name: "bob", Age: 7}} {
+		if u.Age >= age {
+			list = append(list, u)
+		}
+	}
+	return list, nil
+}
+
+// greet prints the greeting itself.
+//
+//gofire:command silent
+func greet(name string) string {
+	msg := "hello " + name
+	fmt.Println(msg)
+	return msg
+}
@@ -0,0 +1,16 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// add sums two numbers.
+func add(a, b int) int {
+	return a + b
+}
+
+// mul multiplies two numbers
+// by provided factor.
+func mul(a int, factor *int) {
+	fmt.Println(a * (*factor))
+}
@@ -18,40 +18,50 @@ const (
 	DriverNameBubbleTea DriverName = "bubbletea"
 )
 
-// Reference helps to represent full qualified group name.
-type Reference string
+// Reference helps to represent full qualified group field path,
+// the path could be arbitrary deep for nested groups fields.
+type Reference struct {
+	typ, group string
+	path       []string
+}
 
-func NewReference(typ, g, f string) *Reference {
-	ref := Reference(fmt.Sprintf("%s.%s.%s", typ, g, f))
-	return &ref
+func NewReference(typ, g string, path ...string) *Reference {
+	return &Reference{typ: typ, group: g, path: path}
 }
 
 func (g *Reference) Type() string {
 	if g == nil {
 		return ""
 	}
-	return strings.Split(string(*g), ".")[0]
+	return g.typ
 }
 
 func (g *Reference) Group() string {
 	if g == nil {
 		return ""
 	}
-	return strings.Split(string(*g), ".")[1]
+	return g.group
+}
+
+func (g *Reference) Path() []string {
+	if g == nil {
+		return nil
+	}
+	return g.path
 }
 
 func (g *Reference) Field() string {
 	if g == nil {
 		return ""
 	}
-	return strings.Split(string(*g), ".")[2]
+	return strings.Join(g.path, ".")
 }
 
 func (g *Reference) Untyped() string {
 	if g == nil {
 		return ""
 	}
-	return strings.Join(strings.Split(string(*g), ".")[1:], ".")
+	return fmt.Sprintf("%s.%s", g.group, g.Field())
 }
 
 type Parameter struct {
@@ -62,6 +72,13 @@ type Parameter struct {
 	Ellipsis bool
 	Doc      string
 	Ref      *Reference
+	Named    gofire.Typ
+	// Repeatable parameter holds a slice of values, one for every repeatable group index.
+	Repeatable bool
+	// Env parameter falls back to the environment variable value if it isn't provided.
+	Env string
+	// Required parameter has to be provided, otherwise the command fails.
+	Required bool
 }
 
 type Driver interface {
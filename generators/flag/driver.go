@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/config"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -20,29 +21,66 @@ type driver struct {
 	preParse  bytes.Buffer
 	postParse bytes.Buffer
 	usageList []string
+	argList   []string
+	argDocs   []string
 	printList []string
+	envs      map[string]string
+	required  []string
+	xor       map[string][]string
+	with      map[string][]string
+	implies   map[string]string
+	provided  bool
 }
 
 func (d driver) Output(cmd gofire.Command) (string, error) {
 	var buf bytes.Buffer
-	if _, err := buf.WriteString(
+	if _, err := fmt.Fprintf(
+		&buf,
 		`
+			// reset command line flag set so the command could be run multiple times.
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+			flag.CommandLine.Usage = func() { flag.Usage() }
 			defer func() {
-				if err != nil {
+				if %s {
 					flag.Usage()
 				}
 			}()
 		`,
+		internal.Failed(cmd),
 	); err != nil {
 		return "", err
 	}
 	if _, err := buf.Write(d.preParse.Bytes()); err != nil {
 		return "", err
 	}
+	if cmd.Config {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				var _config string
+				flag.StringVar(&_config, %q, "", %q)
+				var _printConfig bool
+				flag.BoolVar(&_printConfig, %q, false, %q)
+			`,
+			config.Flag,
+			internal.ConfigDoc,
+			config.PrintFlag,
+			internal.PrintConfigDoc,
+		); err != nil {
+			return "", err
+		}
+		d.usageList = append(d.usageList, fmt.Sprintf("-%s=%q", config.Flag, ""), fmt.Sprintf("-%s=false", config.PrintFlag))
+		d.printList = append(
+			d.printList,
+			fmt.Sprintf("-%s string %s (default %q)", config.Flag, internal.ConfigDoc, ""),
+			fmt.Sprintf("-%s bool %s (default false)", config.PrintFlag, internal.PrintConfigDoc),
+		)
+	}
 	sort.Strings(d.usageList)
 	sort.Strings(d.printList)
-	u := strings.Join(d.usageList, " ")
-	p := strings.Join(d.printList, " ")
+	// Arguments are kept in their positional order after the flags.
+	u := strings.Join(append(d.usageList, d.argList...), " ")
+	p := strings.Join(append(d.printList, d.argDocs...), " ")
 	if _, err := fmt.Fprintf(
 		&buf,
 		`
@@ -59,8 +97,8 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 				}
 			}
 		`,
-		cmd.Doc,
-		fmt.Sprintf("%s %s [-help -h]", cmd.Function, u),
+		internal.Manual(cmd),
+		fmt.Sprintf("%s %s [-help -h]", cmd.Title(), u),
 		fmt.Sprintf("%s, %s", cmd.Definition, p),
 	); err != nil {
 		return "", err
@@ -68,6 +106,77 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 	if _, err := buf.WriteString("flag.Parse()"); err != nil {
 		return "", err
 	}
+	if len(d.envs) > 0 {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				{
+					provided := make(map[string]bool)
+					flag.Visit(func(f *flag.Flag) { provided[f.Name] = true })
+					changed := func(name string) bool { return provided[name] }
+					%s
+				}
+			`,
+			internal.Envs(d.envs, "changed", "flag.Set"),
+		); err != nil {
+			return "", err
+		}
+	}
+	if cmd.Config {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				{
+					provided := make(map[string]bool)
+					flag.Visit(func(f *flag.Flag) { provided[f.Name] = true })
+					defined := func(name string) bool { return flag.Lookup(name) != nil }
+					changed := func(name string) bool { return provided[name] }
+					%s
+				}
+			`,
+			internal.Config(
+				"defined",
+				"changed",
+				"flag.Set",
+				"flag.VisitAll(func(f *flag.Flag) { values[f.Name] = f.Value.String() })",
+			),
+		); err != nil {
+			return "", err
+		}
+	}
+	if len(d.required) > 0 || len(d.xor) > 0 || len(d.with) > 0 || len(d.implies) > 0 {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				{
+					provided := make(map[string]bool)
+					flag.Visit(func(f *flag.Flag) { provided[f.Name] = true })
+					changed := func(name string) bool { return provided[name] }
+					%s
+					%s
+					%s
+					%s
+				}
+			`,
+			internal.Required(d.required, "changed"),
+			internal.Exclusive(d.xor, "changed"),
+			internal.Together(d.with, "changed"),
+			internal.Implies(d.implies, "changed"),
+		); err != nil {
+			return "", err
+		}
+	}
+	// Optional flags are reset and checked flags are validated after all flags are resolved, so they need the final provided flags.
+	if d.provided {
+		if _, err := buf.WriteString(
+			`
+				provided := make(map[string]bool)
+				flag.Visit(func(f *flag.Flag) { provided[f.Name] = true })
+			`,
+		); err != nil {
+			return "", err
+		}
+	}
 	if _, err := buf.Write(d.postParse.Bytes()); err != nil {
 		return "", err
 	}
@@ -79,7 +188,15 @@ func (d *driver) Reset() error {
 	d.preParse.Reset()
 	d.postParse.Reset()
 	d.usageList = nil
+	d.argList = nil
+	d.argDocs = nil
 	d.printList = nil
+	d.envs = make(map[string]string)
+	d.required = nil
+	d.xor = make(map[string][]string)
+	d.with = make(map[string][]string)
+	d.implies = make(map[string]string)
+	d.provided = false
 	return nil
 }
 
@@ -89,18 +206,22 @@ func (driver) Name() generators.DriverName {
 
 func (d driver) Imports() []string {
 	return []string{
+		`"errors"`,
 		`"flag"`,
 		`"fmt"`,
+		`"os"`,
 		`"strconv"`,
+		`"strings"`,
+		`"time"`,
+		`gofireconfig "github.com/1pkg/gofire/config"`,
 	}
 }
 
 func (d *driver) VisitArgument(a gofire.Argument) error {
 	_ = d.Driver.VisitArgument(a)
 	p := d.Last()
-	typ := a.Type
-	tp, ok := typ.(gofire.TPrimitive)
-	if !ok {
+	typ, _ := internal.Underlying(a.Type)
+	if !internal.Scalar(typ) {
 		return fmt.Errorf(
 			"driver %s: non primitive argument types are not supported, got an argument %s %s",
 			d.Name(),
@@ -108,22 +229,29 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 			typ.Type(),
 		)
 	}
-	if err := d.argument(p.Name, a.Index, tp, a.Ellipsis); err != nil {
+	offset := d.postParse.Len()
+	if err := d.argument(p.Name, internal.Placeholder(a), a.Index, typ, a.Ellipsis); err != nil {
 		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
 	}
+	internal.Fallback(&d.postParse, offset, fmt.Sprintf("flag.NArg() > %d", a.Index), p.Name, typ, a)
+	d.argList = append(d.argList, internal.ArgumentUsage(a, typ))
+	d.argDocs = append(d.argDocs, internal.ArgumentDoc(a, typ))
 	return nil
 }
 
 func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
+	f.Implies = internal.Implied(f, g)
 	_ = d.Driver.VisitFlag(f, g)
 	p := d.Last()
+	if p.Repeatable {
+		return fmt.Errorf("driver %s: repeatable groups are not supported, got a flag %s", d.Name(), p.Name)
+	}
 	typ := p.Type
 	tptr, ptr := typ.(gofire.TPtr)
 	if ptr {
 		typ = tptr.ETyp
 	}
-	tprim, ok := typ.(gofire.TPrimitive)
-	if !ok {
+	if !internal.Scalar(typ) {
 		return fmt.Errorf(
 			"driver %s: non pointer and non primitive flag types are not supported, got a flag %s %s",
 			d.Name(),
@@ -135,28 +263,57 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	if p.Ref != nil {
 		flag = fmt.Sprintf("%s.%s", p.Ref.Group(), flag)
 	}
-	if err := d.flag(p.Name, flag, tprim, ptr, f.Default, p.Doc); err != nil {
+	if err := d.flag(p.Name, flag, typ, ptr, f.Default, p.Doc); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	changed := fmt.Sprintf("provided[%q]", flag)
+	if _, err := d.postParse.WriteString(internal.Enum(flag, p.Name+"_", changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.postParse.WriteString(internal.Constraints(flag, p.Name+"_", changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.postParse.WriteString(internal.Optional(p.Name, changed, f)); err != nil {
 		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
 	}
+	if p.Env != "" {
+		d.envs[flag] = p.Env
+	}
+	if p.Required {
+		d.required = append(d.required, flag)
+	}
+	if f.Optional || len(f.Enum) > 0 || internal.Constrained(f) {
+		d.provided = true
+	}
+	if f.Xor != "" {
+		d.xor[f.Xor] = append(d.xor[f.Xor], flag)
+	}
+	if f.With != "" {
+		d.with[f.With] = append(d.with[f.With], flag)
+	}
+	if f.Implies != "" {
+		d.implies[flag] = f.Implies
+	}
 	return nil
 }
 
-func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellipsis bool) error {
+func (d *driver) argument(name, label string, index uint64, t gofire.Typ, ellipsis bool) error {
 	k := t.Kind()
 	if ellipsis {
 		switch k {
 		case gofire.Bool:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < pflag.NArg(); i++ {
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
 						v, err := strconv.ParseBool(pflag.Arg(i))
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, v)
 					}
 				`,
 				index,
+				label,
 				name,
 				name,
 			); err != nil {
@@ -165,15 +322,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < flag.NArg(); i++ {
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
 						v, err := strconv.ParseInt(flag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -184,16 +342,17 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < flag.NArg(); i++ {
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
 						v, err := strconv.ParseUint(flag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 					
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -204,15 +363,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Float32, gofire.Float64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < flag.NArg(); i++ {
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
 						v, err := strconv.ParseFloat(flag.Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -233,6 +393,63 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			); err != nil {
 				return err
 			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
+						v, err := time.ParseDuration(flag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
+						v, err := time.Parse(%q, flag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < flag.NArg(); i++ {
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "flag.Arg(i)"),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf(
 				"ellipsis type %s is not supported for an argument %s",
@@ -246,18 +463,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{	
-						const i = %d
+						const i, arg = %d, %q
 						if flag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseBool(flag.Arg(i))
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = v
 					}
 				`,
 				index,
+				label,
 				name,
 			); err != nil {
 				return err
@@ -266,18 +484,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if flag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseInt(flag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -288,18 +507,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if flag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseUint(flag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -310,18 +530,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if flag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseFloat(flag.Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -332,14 +553,81 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if flag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						%s = flag.Arg(i)
 					}
 				`,
 				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if flag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.ParseDuration(flag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if flag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.Parse(%q, flag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if flag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "flag.Arg(i)"),
 				name,
 			); err != nil {
 				return err
@@ -352,21 +640,23 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			)
 		}
 	}
-	symb := "arg"
-	if ellipsis {
-		symb += "..."
-	}
-	d.usageList = append(d.usageList, fmt.Sprintf("arg%d", index))
-	d.printList = append(d.printList, fmt.Sprintf("%s %d %s", symb, index, t.Type()))
 	return nil
 }
 
-func (d *driver) flag(name string, flag string, t gofire.TPrimitive, ptr bool, val interface{}, doc string) error {
+func (d *driver) flag(name string, flag string, t gofire.Typ, ptr bool, val interface{}, doc string) error {
 	k := t.Kind()
 	var amp string
 	if ptr {
 		amp = "&"
 	}
+	// Time flags are parsed from their string representation accordingly to the layout.
+	if k == gofire.Time {
+		return d.tflag(name, flag, t.(gofire.TTime), amp, val, doc)
+	}
+	// Text flags are parsed from their raw string representation through the type interface.
+	if k == gofire.Text {
+		return d.xflag(name, flag, t.(gofire.TText), amp, val, doc)
+	}
 	var tp string
 	switch k {
 	case gofire.Bool:
@@ -379,6 +669,8 @@ func (d *driver) flag(name string, flag string, t gofire.TPrimitive, ptr bool, v
 		tp = gofire.Float64.Type()
 	case gofire.String:
 		tp = k.Type()
+	case gofire.Duration:
+		tp = k.Type()
 	default:
 		return fmt.Errorf(
 			"type %s is not supported for a flag %s",
@@ -393,7 +685,7 @@ func (d *driver) flag(name string, flag string, t gofire.TPrimitive, ptr bool, v
 		`,
 		name,
 		tp,
-		strings.Title(tp),
+		internal.Verb(tp),
 		name,
 		flag,
 		t.Format(val),
@@ -416,7 +708,91 @@ func (d *driver) flag(name string, flag string, t gofire.TPrimitive, ptr bool, v
 	); err != nil {
 		return err
 	}
-	d.usageList = append(d.usageList, fmt.Sprintf("-%s=%s", flag, t.Format(val)))
-	d.printList = append(d.printList, fmt.Sprintf("-%s %s %s (default %s)", flag, t.Type(), doc, t.Format(val)))
+	d.usageList = append(d.usageList, fmt.Sprintf("-%s=%s", flag, internal.Usage(t, val)))
+	d.printList = append(d.printList, fmt.Sprintf("-%s %s %s (default %s)", flag, t.Type(), doc, internal.Usage(t, val)))
+	return nil
+}
+
+func (d *driver) tflag(name string, flag string, t gofire.TTime, amp string, val interface{}, doc string) error {
+	if _, err := fmt.Fprintf(&d.preParse,
+		`
+			var %s_ string
+			flag.StringVar(&%s_, %q, %s, %q)
+		`,
+		name,
+		name,
+		flag,
+		internal.TimeDefault(t, val),
+		doc,
+	); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(
+		&d.postParse,
+		`
+			{
+				var v time.Time
+				if %s_ != "" {
+					t, err := time.Parse(%q, %s_)
+					if err != nil {
+						return fmt.Errorf("flag %s parse error: %%v", err)
+					}
+					v = t
+				}
+				%s = %sv
+			}
+		`,
+		name,
+		t.TimeLayout(),
+		name,
+		flag,
+		name,
+		amp,
+	); err != nil {
+		return err
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("-%s=%s", flag, internal.Usage(t, val)))
+	d.printList = append(d.printList, fmt.Sprintf("-%s %s %s (default %s)", flag, t.Type(), doc, internal.Usage(t, val)))
+	return nil
+}
+
+func (d *driver) xflag(name string, flag string, t gofire.TText, amp string, val interface{}, doc string) error {
+	if _, err := fmt.Fprintf(&d.preParse,
+		`
+			var %s_ string
+			flag.StringVar(&%s_, %q, %s, %q)
+		`,
+		name,
+		name,
+		flag,
+		t.Format(val),
+		doc,
+	); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(
+		&d.postParse,
+		`
+			{
+				var v %s
+				if %s_ != "" {
+					if err := %s; err != nil {
+						return fmt.Errorf("flag %s parse error: %%v", err)
+					}
+				}
+				%s = %sv
+			}
+		`,
+		t.Type(),
+		name,
+		internal.Unmarshal(t, "v", name+"_"),
+		flag,
+		name,
+		amp,
+	); err != nil {
+		return err
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("-%s=%s", flag, internal.Usage(t, val)))
+	d.printList = append(d.printList, fmt.Sprintf("-%s %s %s (default %s)", flag, t.Type(), doc, internal.Usage(t, val)))
 	return nil
 }
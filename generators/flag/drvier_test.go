@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
+	"github.com/1pkg/gofire/cmd"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
 
 func TestFlagDriver(t *testing.T) {
+	// test cases imported packages have to be resolved the same way test cases are executed.
+	t.Setenv("GO111MODULE", "off")
 	table := map[string]struct {
 		dir      string
 		pckg     string
@@ -25,7 +30,7 @@ func TestFlagDriver(t *testing.T) {
 			pckg:     "main",
 			function: "echo",
 			params:   []string{"-a=test", "-b", "100", "-c", "10", "-d=true", "-e", "10.125", "test1", "101", "11", "false", "-10.5"},
-			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:-10.500\n",
+			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:-10.500\n0\n",
 		},
 		"echo primitive params types should produce expected output on help flag": {
 			dir:      "echo_primitive_params",
@@ -33,8 +38,8 @@ func TestFlagDriver(t *testing.T) {
 			function: "echo",
 			params:   []string{"-help"},
 			out: `echo documentation string.
-echo -a="" -b=0 -c=0 -d=false -e=0.000000 arg0 arg1 arg2 arg3 arg4 [-help -h]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
+echo -a="" -b=0 -c=0 -d=false -e=0.000000 A1 B1 C1 D1 E1 [-help -h]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
 `,
 		},
 		"echo primitive params types should produce expected error on invalid flags": {
@@ -45,8 +50,8 @@ func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32,
 			err:      errors.New("exit status 1"),
 			out: `invalid value "test" for flag -e: parse error
 echo documentation string.
-echo -a="" -b=0 -c=0 -d=false -e=0.000000 arg0 arg1 arg2 arg3 arg4 [-help -h]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
+echo -a="" -b=0 -c=0 -d=false -e=0.000000 A1 B1 C1 D1 E1 [-help -h]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
 exit status 2
 `,
 		},
@@ -57,9 +62,9 @@ exit status 2
 			params:   []string{"-a=test", "-b", "100", "-c", "10", "-d=true", "-e", "10.125", "test1"},
 			err:      errors.New("exit status 1"),
 			out: `echo documentation string.
-echo -a="" -b=0 -c=0 -d=false -e=0.000000 arg0 arg1 arg2 arg3 arg4 [-help -h]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
-argument 1-th is required
+echo -a="" -b=0 -c=0 -d=false -e=0.000000 A1 B1 C1 D1 E1 [-help -h]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, -a string (default "") -b int (default 0) -c uint64 (default 0) -d bool (default false) -e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
+argument B1 is required
 exit status 2
 `,
 		},
@@ -101,11 +106,930 @@ exit status 2
 			params:   []string{"1", "10", "100"},
 			out:      "[1 10 100]\n",
 		},
+		"echo imported group params should produce expected output on valid params": {
+			dir:      "echo_imported_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-opts.Host=remote", "-opts.Port=3306"},
+			out:      "remote:3306\n",
+		},
+		"echo imported group params should produce expected output on help flag": {
+			dir:      "echo_imported_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -opts.Host="localhost" -opts.Port=5432 [-help -h]
+func echo(opts db.Options), -opts.Host string Options holds database connection options. database host. (default "localhost") -opts.Port uint16 Options holds database connection options. database port. (default 5432)
+`,
+		},
+		"echo embedded group params should produce expected output on valid params": {
+			dir:      "echo_embedded_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-opts.Verbose", "-opts.tls.Cert=cert.pem", "-opts.Port=80"},
+			out:      "true cert.pem 80\n",
+		},
+		"echo embedded group params should produce expected output on help flag": {
+			dir:      "echo_embedded_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -opts.Port=8080 -opts.Verbose=false -opts.tls.Cert="" [-help -h]
+func echo(opts serve), -opts.Port int serve holds serve options. serve port. (default 8080) -opts.Verbose bool serve holds serve options. verbose output. (default false) -opts.tls.Cert string serve holds serve options. certificate path. (default "")
+`,
+		},
+		"echo nested group params should produce expected output on valid params": {
+			dir:      "echo_nested_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-cfg.db.host=remote", "-cfg.db.tls.cert=cert.pem"},
+			out:      "remote cert.pem localhost\n",
+		},
+		"echo time params should produce expected output on valid params": {
+			dir:      "echo_time_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-timeout=1.5s", "-opt.Until=2022-03-04", "2021-02-03T04:05:06Z"},
+			out:      "1.5s 2021-02-03T04:05:06Z 30s 2022-03-04\n",
+		},
+		"echo text params should produce expected output on valid params": {
+			dir:      "echo_text_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-lvl=debug", "10.0.0.1"},
+			out:      "debug 10.0.0.1 127.0.0.1\n",
+		},
+		"echo parser params should produce expected output on valid params": {
+			dir:      "echo_parser_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-opt.Region=us-2"},
+			out:      "us 2\n",
+		},
+		"echo parser params should produce expected output on default params": {
+			dir:      "echo_parser_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{},
+			out:      "eu 1\n",
+		},
+		"echo text params should produce expected output on help flag": {
+			dir:      "echo_text_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -lvl="" -opt.Addr="127.0.0.1" HOST [-help -h]
+func echo(lvl *level, host net.IP, opt opts), -lvl level (default "") -opt.Addr net.IP bind address. (default "127.0.0.1") HOST net.IP
+`,
+		},
+		"echo time params should produce expected output on help flag": {
+			dir:      "echo_time_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -opt.Interval=30s -opt.Until="2021-01-02" -timeout=0s SINCE [-help -h]
+func echo(timeout *time.Duration, since time.Time, opt retry), -opt.Interval time.Duration retry interval. (default 30s) -opt.Until time.Time retry deadline. (default "2021-01-02") -timeout time.Duration (default 0s) SINCE time.Time
+`,
+		},
+		"echo type checked params should produce expected output on valid params": {
+			dir:      "echo_checked_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-timeout=1.5s", "10"},
+			out:      "1.5s 10 2\n",
+		},
+		"echo named params types should produce expected output on valid params": {
+			dir:      "echo_named_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-host=localhost", "-opt.timeout=10", "-opt.retries=3", "8080"},
+			out:      "localhost:8080 timeout:10 retries:3\n",
+		},
+		"echo named params types should produce expected output on values other than typed constants": {
+			dir:      "echo_named_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-host=localhost", "9090"},
+			out:      "localhost:9090 timeout:0 retries:0\n",
+		},
+		"echo named params types should produce expected output on help flag": {
+			dir:      "echo_named_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -host="" -opt.retries=0 -opt.timeout=0 PORT [-help -h]
+func echo(host *Host, port Port, opt opts), -host string (default "") -opt.retries int (default 0) -opt.timeout int (default 0) PORT uint16
+`,
+		},
+		"echo required params should produce expected output on valid params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-conn.Host=localhost", "-conn.User=admin"},
+			out:      "localhost admin 5432\n",
+		},
+		"echo required params should produce expected error on missing params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-conn.Port=6000"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -conn.Host="" -conn.Port=5432 -conn.User="" [-help -h]
+func echo(conn db), -conn.Host string database host. (required) (default "") -conn.Port int database port. (default 5432) -conn.User string database user. (required) (default "")
+required flag(s) "conn.Host", "conn.User" not set
+exit status 2
+`,
+		},
+		"echo enum params should produce expected output on valid params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-log.Format=json", "-log.Verbosity=2", "-log.Level=warn", "debug"},
+			out:      "json 2 2 0\n",
+		},
+		"echo enum params should produce expected output on constants literal values": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-log.Level=2", "0"},
+			out:      "text 0 2 0\n",
+		},
+		"echo enum params should produce expected output on default params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"info"},
+			out:      "text 0 1 1\n",
+		},
+		"echo enum params should produce expected error on invalid enum flags": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-log.Format=xml", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -log.Format="text" -log.Level="info" -log.Verbosity=0 LVL [-help -h]
+func echo(log logopts, lvl level), -log.Format string log format. (one of text, json) (default "text") -log.Level level log level. (one of debug, info, warn) (default "info") -log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag log.Format value xml is not one of text, json
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on provided default value outside of enum": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-log.Verbosity=0", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -log.Format="text" -log.Level="info" -log.Verbosity=0 LVL [-help -h]
+func echo(log logopts, lvl level), -log.Format string log format. (one of text, json) (default "text") -log.Level level log level. (one of debug, info, warn) (default "info") -log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag log.Verbosity value 0 is not one of 1, 2, 3
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on invalid constant names": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-log.Level=error", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -log.Format="text" -log.Level="info" -log.Verbosity=0 LVL [-help -h]
+func echo(log logopts, lvl level), -log.Format string log format. (one of text, json) (default "text") -log.Level level log level. (one of debug, info, warn) (default "info") -log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag log.Level parse error: error is not one of debug, info, warn
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected output on valid params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-srv.Host=api.local", "-srv.Port=443", "-srv.Name=web"},
+			out:      "api.local 443 web\n",
+		},
+		"echo constrained params should produce expected output on default params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "localhost 8080 \n",
+		},
+		"echo constrained params should produce expected error on out of range flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-srv.Port=70000"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -srv.Host="localhost" -srv.Name="" -srv.Port=8080 [-help -h]
+func echo(srv server), -srv.Host string server host. (default "localhost") -srv.Name string server name. (default "") -srv.Port int server port. (default 8080)
+flag srv.Port value 70000 is greater than max 65535
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on not matching pattern flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-srv.Host=API"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -srv.Host="localhost" -srv.Name="" -srv.Port=8080 [-help -h]
+func echo(srv server), -srv.Host string server host. (default "localhost") -srv.Name string server name. (default "") -srv.Port int server port. (default 8080)
+flag srv.Host value "API" doesn't match pattern ^[a-z.]+$
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on provided default value outside of constraints": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-srv.Name="},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -srv.Host="localhost" -srv.Name="" -srv.Port=8080 [-help -h]
+func echo(srv server), -srv.Host string server host. (default "localhost") -srv.Name string server name. (default "") -srv.Port int server port. (default 8080)
+flag srv.Name value length 0 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo implied params should produce expected output on valid params": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-sec.Cert=cert.pem", "-sec.Key=key.pem"},
+			out:      "cert.pem key.pem\n",
+		},
+		"echo implied params should produce expected output on implied flag only": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-sec.Key=key.pem"},
+			out:      " key.pem\n",
+		},
+		"echo implied params should produce expected error on missing implied flag": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-sec.Cert=cert.pem"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -sec.Cert="" -sec.Key="" [-help -h]
+func echo(sec tls), -sec.Cert string tls certificate path. (implies sec.Key) (default "") -sec.Key string tls key path. (default "")
+flag sec.Cert requires flag sec.Key to be provided
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on too short flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-srv.Name=w"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -srv.Host="localhost" -srv.Name="" -srv.Port=8080 [-help -h]
+func echo(srv server), -srv.Host string server host. (default "localhost") -srv.Name string server name. (default "") -srv.Port int server port. (default 8080)
+flag srv.Name value length 1 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo related params should produce expected output on valid params": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-src.File=in.txt", "-sec.Cert=c.pem", "-sec.Key=k.pem"},
+			out:      "in.txt  c.pem k.pem\n",
+		},
+		"echo related params should produce expected error on mutually exclusive flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-src.File=in.txt", "-src.URL=http://in"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -sec.Cert="" -sec.Key="" -src.File="" -src.URL="" [-help -h]
+func echo(src source, sec tls), -sec.Cert string tls certificate path. (with tls) (default "") -sec.Key string tls key path. (with tls) (default "") -src.File string input file path. (xor input) (default "") -src.URL string input url. (xor input) (default "")
+flag(s) "src.File", "src.URL" of group input are mutually exclusive
+exit status 2
+`,
+		},
+		"echo related params should produce expected error on not together flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-sec.Cert=c.pem"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -sec.Cert="" -sec.Key="" -src.File="" -src.URL="" [-help -h]
+func echo(src source, sec tls), -sec.Cert string tls certificate path. (with tls) (default "") -sec.Key string tls key path. (with tls) (default "") -src.File string input file path. (xor input) (default "") -src.URL string input url. (xor input) (default "")
+flag(s) "sec.Key" of group tls not set, required together with "sec.Cert"
+exit status 2
+`,
+		},
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false 30 nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-lim.Retries=3"},
+			out:      "false 30 3\n",
+		},
+		"echo directive params should produce expected output on default params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "6\n",
+		},
+		"echo directive params should produce expected output on valid params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-mult=3", "1", "2"},
+			out:      "9\n",
+		},
+		"echo directive params should produce expected error on missing argument": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-mult=3", "1"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -mult=2 LEFT RIGHT [-help -h]
+func echo(a int, b int, mult *int), -mult int result multiplier. (default 2) LEFT int left operand. RIGHT int
+argument RIGHT is required
+exit status 2
+`,
+		},
+		"echo named command should produce expected output on valid params": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"hello"},
+			out:      "hello\n",
+		},
+		"echo named command should produce expected error on missing argument": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			err:      errors.New("exit status 1"),
+			out: `echo prints the provided text.
+aliases: et
+examples:
+	echo-text hello
+echo-text TEXT [-help -h]
+func echo(text string), TEXT string
+argument TEXT is required
+exit status 2
+`,
+		},
+		"echo documented args should produce expected output on valid params": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "3\n",
+		},
+		"echo documented args should produce expected error on missing argument": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo LEFT RIGHT [-help -h]
+func echo(left int, right int /* right operand. */), LEFT int left operand. RIGHT int right operand.
+argument RIGHT is required
+exit status 2
+`,
+		},
+		"echo optional args should produce expected output on omitted optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt"},
+			out:      "log.txt 10\n",
+		},
+		"echo optional args should produce expected output on provided optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt", "5"},
+			out:      "log.txt 5\n",
+		},
+		"echo optional args should produce expected error on missing argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			err:      errors.New("exit status 1"),
+			out: `tail prints the file lines.
+tail FILE [LINES=10] [-help -h]
+func tail(file string, lines int), FILE string LINES int number of lines. (default 10)
+argument FILE is required
+exit status 2
+`,
+		},
+		"echo output results should produce expected text output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"10"},
+			out:      "NAME   AGE\nalice  30\n",
+		},
+		"echo output results should produce expected json output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=json", "0"},
+			out: `[
+  {
+    "name": "alice",
+    "age": 30
+  },
+  {
+    "name": "bob",
+    "age": 7
+  }
+]
+`,
+		},
+		"echo output results should produce expected yaml output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"-output", "yaml", "0"},
+			out: `- name: alice
+  age: 30
+- name: bob
+  age: 7
+`,
+		},
+		"echo output results should produce expected error on error result": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"200"},
+			err:      errors.New("exit status 1"),
+			out: `age is out of range
+exit status 2
+`,
+		},
+		"echo output results should produce expected error on unsupported output format": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=xml", "0"},
+			err:      errors.New("exit status 1"),
+			out: `output format "xml" is not supported, one of text, json, yaml is expected
+exit status 2
+`,
+		},
+		"echo output results should not print silent command results": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "greet",
+			params:   []string{"bob"},
+			out:      "hello bob\n",
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function})
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestFlagDriverTree(t *testing.T) {
+	// tree name is derived from random temporary directory name.
+	unify := regexp.MustCompile(`(?m)^\d+ command`)
+	table := map[string]struct {
+		dir       string
+		pckg      string
+		functions []string
+		params    []string
+		out       string
+		err       error
+	}{
+		"echo tree should produce expected output on valid command params": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"mul", "-factor=3", "10"},
+			out:       "30\n",
+		},
+		"echo tree should produce expected output on printed command results": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"add", "--output=json", "10", "20"},
+			out:       "30\n",
+		},
+		"echo tree should produce expected output on help flag": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"--help"},
+			out: `tree command [--help -h]
+commands:
+	add	add sums two numbers.
+	mul	mul multiplies two numbers
+`,
+		},
+		"echo tree should produce expected error on unknown command": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"sub", "1", "2"},
+			err:       errors.New("exit status 1"),
+			out: `tree command [--help -h]
+commands:
+	add	add sums two numbers.
+	mul	mul multiplies two numbers
+command "sub" is unknown
+exit status 2
+`,
+		},
+		"echo methods tree should produce expected output on valid command params": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"Fetch", "-c.retries=3", "res"},
+			out:       "fetch res from localhost with 3 retries\n",
+		},
+		"echo methods tree should produce expected output on shared params before command": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"-c.addr", "remote", "-c.retries=3", "Fetch", "res"},
+			out:       "fetch res from remote with 3 retries\n",
+		},
+		"echo methods tree should produce expected output on help flag": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client"},
+			params:    []string{"Ping", "-help"},
+			out: `Ping pings the remote.
+Ping -c.addr="localhost" -c.retries=0 [-help -h]
+func (c client) Ping(), -c.addr string client is a simple remote client. remote address. (default "localhost") -c.retries int client is a simple remote client. (default 0)
+`,
+		},
+		"echo method should produce expected output on valid params": {
+			dir:       "echo_methods",
+			pckg:      "main",
+			functions: []string{"client.Fetch"},
+			params:    []string{"-c.addr=remote", "res"},
+			out:       "fetch res from remote with 0 retries\n",
+		},
+		"echo package tree should produce expected output on valid command params": {
+			dir:    "echo_package",
+			pckg:   "main",
+			params: []string{"Neg", "10"},
+			out:    "-10\n",
+		},
+		"echo package tree should produce expected output on help flag": {
+			dir:    "echo_package",
+			pckg:   "main",
+			params: []string{"help"},
+			out: `tree command [--help -h]
+commands:
+	Add	Add sums two numbers.
+	Neg	Neg negates a number.
+`,
+		},
+		"echo tree should produce expected error on command invalid args": {
+			dir:       "echo_tree",
+			pckg:      "main",
+			functions: []string{"add", "mul"},
+			params:    []string{"add", "1"},
+			err:       errors.New("exit status 1"),
+			out: `add sums two numbers.
+add A B [-help -h]
+func add(a, b int) int, A int B int
+argument B is required
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.functions)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if out = unify.ReplaceAllString(out, "tree command"); tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestFlagDriverChain(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo chain should produce expected output without chained method": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "open",
+			params:   []string{"-path=x"},
+			out:      "open x\n",
+		},
+		"echo chain should produce expected output on valid chained method flags": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "open",
+			params:   []string{"-path=x", "then", "Stat", "-deep"},
+			out:      "open x\nstat x deep true\n",
+		},
+		"echo chain should produce expected output on valid chained method args": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "open",
+			params:   []string{"-path=x", "then", "Read", "10"},
+			out:      "open x\nread 10 bytes of x\n",
+		},
+		"echo chain should produce expected error on missing chained method": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "open",
+			params:   []string{"-path=x", "then"},
+			err:      errors.New("exit status 1"),
+			out:      "open x\nchained method is required\nexit status 2\n",
+		},
+		"echo chain should produce expected error on unknown chained method": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "open",
+			params:   []string{"-path=x", "then", "Write"},
+			err:      errors.New("exit status 1"),
+			out:      "open x\nmethod \"Write\" can't be chained\nexit status 2\n",
+		},
+		"echo chain should produce expected output on escaped chaining argument": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "touch",
+			params:   []string{"--", "then"},
+			out:      "touch then\n",
+		},
+		"echo chain should produce expected output on chained method after positional args": {
+			dir:      "echo_chain",
+			pckg:     "main",
+			function: "touch",
+			params:   []string{"x", "then", "Read", "10"},
+			out:      "touch x\nread 10 bytes of x\n",
+		},
 	}
 	for tname, tcase := range table {
 		t.Run(tname, func(t *testing.T) {
 			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
-			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.function)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithChain())
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestFlagDriverEnv(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		env      map[string]string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo env params should produce expected output on env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_VERBOSE": "true", "APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			out:      "true remote 6000\n",
+		},
+		"echo env params should prefer explicit flags over env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			params:   []string{"-conn.Host=local"},
+			out:      "false local 6000\n",
+		},
+		"echo env params should produce expected output on default params": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false localhost 5432\n",
+		},
+		"echo env params should produce expected error on invalid env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"DB_PORT": "port"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -conn.Host="localhost" -conn.Port=5432 -verbose=false [-help -h]
+func echo(verbose *bool, conn db), -conn.Host string database host. (env APP_CONN_HOST) (default "localhost") -conn.Port int database port. (env DB_PORT) (default 5432) -verbose bool (env APP_VERBOSE) (default false)
+env DB_PORT parse error: parse error
+exit status 2
+`,
+		},
+		"echo env params should produce expected output on help flag": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-help"},
+			out: `echo documentation string.
+echo -conn.Host="localhost" -conn.Port=5432 -verbose=false [-help -h]
+func echo(verbose *bool, conn db), -conn.Host string database host. (env APP_CONN_HOST) (default "localhost") -conn.Port int database port. (env DB_PORT) (default 5432) -verbose bool (env APP_VERBOSE) (default false)
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			for k, v := range tcase.env {
+				t.Setenv(k, v)
+			}
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithEnvPrefix("APP"))
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestFlagDriverOptional(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "nil nil nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-verbose", "-lim.Retries=3"},
+			out:      "true nil 3\n",
+		},
+		"echo optional params should produce expected output on params set to defaults": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"-verbose=false", "-lim.Timeout=30"},
+			out:      "false 30 nil\n",
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithOptional())
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestFlagDriverConfig(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		config   string
+		data     string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo config params should produce expected output on json config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"verbose": true, "db": {"Host": "remote", "Port": 6000}}`,
+			out:      "true remote 6000\n",
+		},
+		"echo config params should produce expected output on yaml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Host: remote\n",
+			out:      "false remote 5432\n",
+		},
+		"echo config params should produce expected output on toml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "verbose = true\n[db]\nPort = 6000\n",
+			out:      "true localhost 6000\n",
+		},
+		"echo config params should prefer explicit flags over config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Host": "remote", "Port": 6000}}`,
+			params:   []string{"-db.Host=local"},
+			out:      "false local 6000\n",
+		},
+		"echo config params should produce expected output on print config flag": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Port: 6000\n",
+			params:   []string{"-verbose", "-print-config"},
+			out: `{
+  "db": {
+    "Host": "localhost",
+    "Port": "6000"
+  },
+  "verbose": "true"
+}
+`,
+		},
+		"echo config params should produce expected error on unknown config keys": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Name": "test"}}`,
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -config="" -db.Host="localhost" -db.Port=5432 -print-config=false -verbose=false [-help -h]
+func echo(verbose *bool, db conn), -config string path to the configuration file (json, yaml or toml). (default "") -db.Host string database host. (default "localhost") -db.Port int database port. (default 5432) -print-config bool print the effective configuration and exit. (default false) -verbose bool (default false)
+config key db.Name is unknown
+exit status 2
+`,
+		},
+		"echo config params should produce expected error on invalid config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "[db]\nPort = 'port'\n",
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo -config="" -db.Host="localhost" -db.Port=5432 -print-config=false -verbose=false [-help -h]
+func echo(verbose *bool, db conn), -config string path to the configuration file (json, yaml or toml). (default "") -db.Host string database host. (default "localhost") -db.Port int database port. (default 5432) -print-config bool print the effective configuration and exit. (default false) -verbose bool (default false)
+config key db.Port parse error: parse error
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tcase.config)
+			if err := os.WriteFile(path, []byte(tcase.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			params := append([]string{"-config=" + path}, tcase.params...)
+			exec := internal.GoExec("run -tags=tcases .", params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameFlag, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithConfig())
 			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
 				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
 			}
@@ -0,0 +1,36 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// file is a simple opened file.
+type file struct {
+	path string
+}
+
+// open opens a file by its path.
+//
+//gofire:command silent
+func open(path *string) *file {
+	fmt.Printf("open %s\n", *path)
+	return &file{path: *path}
+}
+
+// touch creates a file by its path.
+//
+//gofire:command silent
+func touch(path string) file {
+	fmt.Printf("touch %s\n", path)
+	return file{path: path}
+}
+
+// Stat prints the file stats.
+func (f *file) Stat(deep *bool) {
+	fmt.Printf("stat %s deep %t\n", f.path, *deep)
+}
+
+// Read reads n bytes of the file.
+func (f file) Read(n int) {
+	fmt.Printf("read %d bytes of %s\n", n, f.path)
+}
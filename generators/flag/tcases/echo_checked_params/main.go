@@ -0,0 +1,16 @@
+//go:build tcases
+
+package main
+
+import (
+	stdctx "context"
+	"fmt"
+	"time"
+)
+
+const size = 2
+
+// echo documentation string.
+func echo(_ stdctx.Context, timeout *time.Duration, n byte) {
+	fmt.Println(*timeout, n, size)
+}
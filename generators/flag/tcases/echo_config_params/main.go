@@ -0,0 +1,19 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type conn struct {
+	// database host.
+	Host string `gofire:"default=localhost"`
+	// database port.
+	Port int `gofire:"default=5432"`
+}
+
+// echo documentation string.
+func echo(verbose *bool, db conn) {
+	fmt.Println(*verbose, db.Host, db.Port)
+}
@@ -0,0 +1,16 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+// echo documentation string.
+//
+// Parameters:
+//
+//	left: left operand.
+func echo(left int, right int /* right operand. */) {
+	fmt.Println(left + right)
+}
@@ -0,0 +1,32 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+// common holds shared options.
+type common struct {
+	// verbose output.
+	Verbose bool
+}
+
+// tls holds tls options.
+type tls struct {
+	// certificate path.
+	Cert string
+}
+
+// serve holds serve options.
+type serve struct {
+	common
+	tls `gofire:"nested"`
+	// serve port.
+	Port int `gofire:"default=8080"`
+}
+
+// echo documentation string.
+func echo(opts serve) {
+	fmt.Println(opts.Verbose, opts.tls.Cert, opts.Port)
+}
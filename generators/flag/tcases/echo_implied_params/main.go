@@ -0,0 +1,19 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type tls struct {
+	// tls certificate path.
+	Cert string `gofire:"implies=Key"`
+	// tls key path.
+	Key string
+}
+
+// echo documentation string.
+func echo(sec tls) {
+	fmt.Println(sec.Cert, sec.Key)
+}
@@ -0,0 +1,23 @@
+package dbopts
+
+import "fmt"
+
+// Options holds database connection options.
+type Options struct {
+	// database host.
+	Host string `gofire:"default=localhost"`
+	// database port.
+	Port Port `gofire:"default=5432"`
+	dsn  string
+}
+
+// Port is a database port.
+type Port uint16
+
+// DSN returns options data source name.
+func (o Options) DSN() string {
+	if o.dsn != "" {
+		return o.dsn
+	}
+	return fmt.Sprintf("%s:%d", o.Host, o.Port)
+}
@@ -0,0 +1,14 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+
+	db "github.com/1pkg/gofire/generators/flag/tcases/echo_imported_group/dbopts"
+)
+
+// echo documentation string.
+func echo(opts db.Options) {
+	fmt.Println(opts.DSN())
+}
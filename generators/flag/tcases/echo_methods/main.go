@@ -0,0 +1,25 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// client is a simple remote client.
+type client struct {
+	// remote address.
+	addr    string `gofire:"default=localhost"`
+	retries int
+}
+
+// Fetch fetches a resource by id.
+func (c *client) Fetch(id string) {
+	fmt.Printf("fetch %s from %s with %d retries\n", id, c.addr, c.retries)
+}
+
+// Ping pings the remote.
+func (c client) Ping() {
+	fmt.Printf("ping %s\n", c.addr)
+}
+
+func (c client) reset() {
+}
@@ -0,0 +1,34 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// Port is a network port.
+type Port uint16
+
+func (p Port) String() string {
+	return fmt.Sprintf(":%d", uint16(p))
+}
+
+// DefaultPort is the default network port.
+const DefaultPort Port = 8080
+
+// Host is a network host.
+type Host string
+
+// Retries is a number of retries.
+type Retries = int
+
+type opts struct {
+	timeout Timeout
+	retries Retries
+}
+
+// Timeout is a timeout in seconds.
+type Timeout int
+
+// echo documentation string.
+func echo(host *Host, port Port, opt opts) {
+	fmt.Printf("%s%s timeout:%d retries:%d\n", *host, port, opt.timeout, opt.retries)
+}
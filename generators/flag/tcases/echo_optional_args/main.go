@@ -0,0 +1,14 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+// tail prints the file lines.
+//
+//gofire:arg lines default=10 doc='number of lines.'
+func tail(file string, lines int) {
+	fmt.Println(file, lines)
+}
@@ -0,0 +1,23 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// Add sums two numbers.
+func Add(a, b int) {
+	fmt.Println(a + b)
+}
+
+// Merge is not supported by flag driver.
+func Merge(a, b map[string]int) {
+}
+
+// Neg negates a number.
+func Neg(a int) {
+	fmt.Println(-a)
+}
+
+func sub(a, b int) {
+	fmt.Println(a - b)
+}
@@ -0,0 +1,26 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type source struct {
+	// input file path.
+	File string `gofire:"xor=input"`
+	// input url.
+	URL string `gofire:"xor=input"`
+}
+
+type tls struct {
+	// tls certificate path.
+	Cert string `gofire:"with=tls"`
+	// tls key path.
+	Key string `gofire:"with=tls"`
+}
+
+// echo documentation string.
+func echo(src source, sec tls) {
+	fmt.Println(src.File, src.URL, sec.Cert, sec.Key)
+}
@@ -37,31 +37,78 @@ func Register(name DriverName, driver Driver) {
 
 // Generate generates cli command using provided driver to provided writer output.
 func Generate(ctx context.Context, name DriverName, cmd gofire.Command, w io.Writer) error {
+	driver, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	main := cmd.Package == "main"
+	// the output format option is extracted only by the main entrypoint printing the results.
+	printed := main && (proxy{command: cmd}).Output()
+	if err := collide(printed, append([]gofire.Command{cmd}, cmd.Chain...)...); err != nil {
+		return err
+	}
+	src, err := generate(ctx, driver, cmd, main, false)
+	if err != nil {
+		return err
+	}
+	// chained methods are generated as separate commands
+	// that receive the command result as their receiver.
+	if len(cmd.Chain) > 0 {
+		srcs := [][]byte{src}
+		for _, link := range cmd.Chain {
+			src, err := generate(ctx, driver, link, false, true)
+			if err != nil {
+				return err
+			}
+			srcs = append(srcs, src)
+		}
+		if src, err = merge(srcs...); err != nil {
+			return err
+		}
+	}
+	if src, err = imports.Process("", src, nil); err != nil {
+		return err
+	}
+	if _, err := w.Write(src); err != nil {
+		return err
+	}
+	return nil
+}
+
+func lookup(name DriverName) (Driver, error) {
 	driverMu.Lock()
 	driver, ok := drivers[name]
 	driverMu.Unlock()
 	if !ok {
-		return fmt.Errorf("unknown driver %q (forgotten import?)", name)
+		return nil, fmt.Errorf("unknown driver %q (forgotten import?)", name)
 	}
+	return driver, nil
+}
+
+func generate(ctx context.Context, driver Driver, cmd gofire.Command, main, chained bool) ([]byte, error) {
 	if err := driver.Reset(); err != nil {
-		return err
+		return nil, err
 	}
 	if err := cmd.Accept(driver); err != nil {
-		return err
+		return nil, err
 	}
-	proxy, err := proxify(driver, cmd)
+	proxy, err := proxify(driver, cmd, main, chained)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	tmpl, err := template.New("gen").Parse(driver.Template())
+	return execute(driver.Template(), proxy)
+}
+
+func execute(text string, data interface{}) ([]byte, error) {
+	tmpl, err := template.New("gen").Parse(text)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, proxy); err != nil {
-		return err
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
 	}
-	src := []byte(
+	return []byte(
 		strings.Trim(
 			strings.TrimSpace(
 				stripnl.ReplaceAllString(
@@ -71,12 +118,5 @@ func Generate(ctx context.Context, name DriverName, cmd gofire.Command, w io.Wri
 			),
 			"\n",
 		),
-	)
-	if src, err = imports.Process("", src, nil); err != nil {
-		return err
-	}
-	if _, err := w.Write(src); err != nil {
-		return err
-	}
-	return nil
+	), nil
 }
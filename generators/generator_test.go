@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/1pkg/gofire"
@@ -193,4 +194,239 @@ func TestGeneratorGenerate(t *testing.T) {
 			t.Fatal("generate should produce non empty output")
 		}
 	})
+	t.Run("should fail on flag colliding with output format option", func(t *testing.T) {
+		d.reset = func() error {
+			return nil
+		}
+		d.output = func(gofire.Command) (string, error) {
+			return "", nil
+		}
+		d.template = nil
+		cmd := gofire.Command{
+			Package:  "main",
+			Function: "test_function",
+			Results:  []string{"int"},
+			Parameters: []gofire.Parameter{
+				gofire.Flag{Type: gofire.TPrimitive{TKind: gofire.String}, Full: "output"},
+			},
+		}
+		err := generators.Generate(context.TODO(), generators.DriverName("test_generate"), cmd, nil)
+		if fmt.Sprintf("%v", err) != "flag output of command test_function collides with the output format option" {
+			t.Fatalf("generate should fail on flag colliding with output format option with message %q", err)
+		}
+	})
+	t.Run("should fail on flag colliding with configuration option", func(t *testing.T) {
+		d.reset = func() error {
+			return nil
+		}
+		d.output = func(gofire.Command) (string, error) {
+			return "", nil
+		}
+		d.template = nil
+		cmd := gofire.Command{
+			Package:  "main",
+			Function: "test_function",
+			Config:   true,
+			Parameters: []gofire.Parameter{
+				gofire.Flag{Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}, Full: "config"},
+			},
+		}
+		err := generators.Generate(context.TODO(), generators.DriverName("test_generate"), cmd, nil)
+		if fmt.Sprintf("%v", err) != "flag config of command test_function collides with the configuration option" {
+			t.Fatalf("generate should fail on flag colliding with configuration option with message %q", err)
+		}
+	})
+	t.Run("should report error results and skip printing for silent command", func(t *testing.T) {
+		d.reset = func() error {
+			return nil
+		}
+		d.output = func(gofire.Command) (string, error) {
+			return "", nil
+		}
+		d.template = nil
+		var buf bytes.Buffer
+		cmd := gofire.Command{
+			Package:  "main",
+			Function: "test_function",
+			Silent:   true,
+			Results:  []string{"int", "error"},
+		}
+		err := generators.Generate(context.TODO(), generators.DriverName("test_generate"), cmd, &buf)
+		if fmt.Sprintf("%v", err) != "<nil>" {
+			t.Fatalf("generate should not fail on valid preset %q", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "if o1 != nil {") {
+			t.Fatalf("generate should produce output reporting error results, got %q", out)
+		}
+		if strings.Contains(out, "gofireoutput.Print") {
+			t.Fatalf("generate should produce output without printing silent command results, got %q", out)
+		}
+		if strings.Contains(out, "github.com/1pkg/gofire/output") {
+			t.Fatalf("generate should produce output without output package import for silent command, got %q", out)
+		}
+	})
+}
+
+func TestGeneratorGenerateTree(t *testing.T) {
+	d := &driver{}
+	generators.Register(generators.DriverName("test_generate_tree"), internal.Annotated(d))
+	d.reset = func() error {
+		return nil
+	}
+	d.output = func(gofire.Command) (string, error) {
+		return "", nil
+	}
+	t.Run("should fail on unregistered driver", func(t *testing.T) {
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree_"), gofire.Tree{}, nil)
+		if fmt.Sprintf("%v", err) != `unknown driver "test_generate_tree_" (forgotten import?)` {
+			t.Fatalf("generate tree should fail on unregistered driver with message %q", err)
+		}
+	})
+	t.Run("should fail on duplicated commands", func(t *testing.T) {
+		tree := gofire.Tree{
+			Package: "main",
+			Name:    "test",
+			Commands: []gofire.Command{
+				{Package: "main", Function: "test_function"},
+				{Package: "main", Function: "test_function"},
+			},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, nil)
+		if fmt.Sprintf("%v", err) != "command CommandTest_function is duplicated in tree test" {
+			t.Fatalf("generate tree should fail on duplicated commands with message %q", err)
+		}
+	})
+	t.Run("should fail on driver output error", func(t *testing.T) {
+		d.output = func(gofire.Command) (string, error) {
+			return "", errors.New("test_output")
+		}
+		defer func() {
+			d.output = func(gofire.Command) (string, error) {
+				return "", nil
+			}
+		}()
+		tree := gofire.Tree{
+			Package:  "main",
+			Name:     "test",
+			Commands: []gofire.Command{{Package: "main", Function: "test_function"}},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, nil)
+		if fmt.Sprintf("%v", err) != "test_output" {
+			t.Fatalf("generate tree should fail on driver output error with message %q", err)
+		}
+	})
+	t.Run("should produce result into writer on valid preset", func(t *testing.T) {
+		var buf bytes.Buffer
+		tree := gofire.Tree{
+			Package: "main",
+			Name:    "test-tree",
+			Doc:     "test_doc",
+			Commands: []gofire.Command{
+				{
+					Package:  "main",
+					Function: "first",
+					Doc:      "first_doc",
+					Results:  []string{"int", "string"},
+					Parameters: []gofire.Parameter{
+						gofire.Argument{Type: gofire.TPrimitive{TKind: gofire.Int}, Index: 0},
+					},
+				},
+				{
+					Package:  "main",
+					Function: "second",
+					Context:  true,
+					Parameters: []gofire.Parameter{
+						gofire.Flag{Type: gofire.TPrimitive{TKind: gofire.Int}, Full: "flag", Short: "f"},
+					},
+				},
+			},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, &buf)
+		if fmt.Sprintf("%v", err) != "<nil>" {
+			t.Fatalf("generate tree should not fail on valid preset %q", err)
+		}
+		out := buf.String()
+		for _, expected := range []string{
+			"func CommandFirst(ctx context.Context)",
+			"func CommandSecond(ctx context.Context)",
+			"func CommandTestTree(ctx context.Context) (err error)",
+			"format, args, err := gofireoutput.Extract(os.Args)",
+			"r0, r1, err = CommandFirst(ctx)",
+			"gofireoutput.Print(os.Stdout, format, r0, r1)",
+			"err = CommandSecond(ctx)",
+			"func main()",
+		} {
+			if !strings.Contains(out, expected) {
+				t.Fatalf("generate tree should produce output containing %q, got %q", expected, out)
+			}
+		}
+		if strings.Count(out, "func main()") != 1 {
+			t.Fatalf("generate tree should produce output with single main entrypoint, got %q", out)
+		}
+	})
+	t.Run("should fail on flag colliding with output format option", func(t *testing.T) {
+		tree := gofire.Tree{
+			Package: "main",
+			Name:    "test",
+			Commands: []gofire.Command{
+				{Package: "main", Function: "first", Results: []string{"int"}},
+				{
+					Package:  "main",
+					Function: "second",
+					Parameters: []gofire.Parameter{
+						gofire.Flag{Type: gofire.TPrimitive{TKind: gofire.String}, Full: "output"},
+					},
+				},
+			},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, nil)
+		if fmt.Sprintf("%v", err) != "flag output of command second collides with the output format option" {
+			t.Fatalf("generate tree should fail on flag colliding with output format option with message %q", err)
+		}
+	})
+	t.Run("should fail on duplicated commands aliases", func(t *testing.T) {
+		tree := gofire.Tree{
+			Package: "main",
+			Name:    "test",
+			Commands: []gofire.Command{
+				{Package: "main", Function: "first", Aliases: []string{"f"}},
+				{Package: "main", Function: "second", Name: "f"},
+			},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, nil)
+		if fmt.Sprintf("%v", err) != "command f is duplicated in tree test" {
+			t.Fatalf("generate tree should fail on duplicated commands aliases with message %q", err)
+		}
+	})
+	t.Run("should produce named commands into writer on valid preset", func(t *testing.T) {
+		var buf bytes.Buffer
+		tree := gofire.Tree{
+			Package: "main",
+			Name:    "test",
+			Commands: []gofire.Command{
+				{Package: "main", Function: "syncUsers", Name: "sync-users", Aliases: []string{"su"}, Doc: "sync_doc"},
+				{Package: "main", Function: "debug", Hidden: true, Doc: "debug_doc"},
+			},
+		}
+		err := generators.GenerateTree(context.TODO(), generators.DriverName("test_generate_tree"), tree, &buf)
+		if fmt.Sprintf("%v", err) != "<nil>" {
+			t.Fatalf("generate tree should not fail on valid preset %q", err)
+		}
+		out := buf.String()
+		for _, expected := range []string{
+			"func CommandSyncUsers(ctx context.Context)",
+			"func CommandDebug(ctx context.Context)",
+			`case "sync-users", "su":`,
+			`case "debug":`,
+			`sync-users (su)\tsync_doc`,
+		} {
+			if !strings.Contains(out, expected) {
+				t.Fatalf("generate tree should produce output containing %q, got %q", expected, out)
+			}
+		}
+		if strings.Contains(out, "debug_doc") {
+			t.Fatalf("generate tree should produce output without hidden commands, got %q", out)
+		}
+	})
 }
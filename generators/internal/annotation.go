@@ -16,7 +16,7 @@ func Annotated(d generators.Driver) generators.Driver {
 }
 
 func (d annotation) Imports() []string {
-	return append(d.Driver.Imports(), `"fmt"`, `"os"`, `"os/signal"`)
+	return append(d.Driver.Imports(), `"errors"`, `"fmt"`, `"os"`, `"os/signal"`, `gofireoutput "github.com/1pkg/gofire/output"`)
 }
 
 func (d annotation) Template() string {
@@ -24,16 +24,73 @@ func (d annotation) Template() string {
 		// THIS IS AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
 		// Generated using github.com/1pkg/gofire 🔥 %s.
 	`, time.Now().Format(time.RFC3339)) + d.Driver.Template() + `
-		{{ if eq .Package "main" }}
+		{{ if .Main }}
 			// auto generated main entrypoint.
 			func main() {
 				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 				defer stop()
+				{{ if .Output }}
+					// extract the results output format out of the arguments list.
+					format, args, err := gofireoutput.Extract(os.Args)
+					if err != nil {
+						fmt.Println(err)
+						os.Exit(2)
+					}
+					os.Args = args
+				{{ end }}
+				{{ if .Chain }}
+					// split the chained method call arguments out,
+					// the arguments after the terminator are never chained.
+					var chain []string
+					for i, arg := range os.Args {
+						if arg == "--" {
+							break
+						}
+						if arg == "then" {
+							os.Args, chain = os.Args[:i], os.Args[i:]
+							break
+						}
+					}
+				{{ end }}
 				func({{.Return}}){
+					{{ if .Printed }}
+						// the command has already printed its output instead of calling the function.
+						if errors.Is(err, gofireoutput.ErrPrinted) {
+							return
+						}
+					{{ end }}
 					if err != nil {
 						fmt.Println(err)
 						os.Exit(2)
 					}
+					{{ if .Chain }}
+						if len(chain) == 0 {
+							{{.Print}}
+							return
+						}
+						if len(chain) == 1 {
+							fmt.Println("chained method is required")
+							os.Exit(2)
+						}
+						// shift the chained method name out of the arguments list
+						// so the chained method itself could parse the rest of them.
+						method := chain[1]
+						os.Args = append(os.Args[:1:1], chain[2:]...)
+						switch method {
+						{{ range .Chain }}
+							case {{.Name}}:
+								{{.Call}}
+						{{ end }}
+						default:
+							err = fmt.Errorf("method %q can't be chained", method)
+						}
+						if err != nil {
+							fmt.Println(err)
+							os.Exit(2)
+						}
+					{{ else }}
+						{{.Print}}
+					{{ end }}
 				}({{.Function}}(ctx))
 			}
 		{{ end }}
@@ -1,7 +1,11 @@
 package internal
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/1pkg/gofire"
 	"github.com/1pkg/gofire/generators"
@@ -41,7 +45,7 @@ func (d Driver) Template() string {
 		)
 		
 		{{.Doc}}
-		func {{.Function}}(ctx context.Context) ({{.Return}}) {
+		func {{.Function}}({{.Signature}}) ({{.Return}}) {
 			{{.Vars}}
 			if err = func(ctx context.Context) (err error) {
 				{{.Body}}
@@ -66,7 +70,7 @@ func (d *Driver) VisitPlaceholder(p gofire.Placeholder) error {
 
 func (d *Driver) VisitArgument(a gofire.Argument) error {
 	// For ellipsis argument we need to produce slice like parameter.
-	typ := a.Type
+	typ, named := Underlying(a.Type)
 	if a.Ellipsis {
 		typ = gofire.TSlice{ETyp: typ}
 	}
@@ -74,6 +78,7 @@ func (d *Driver) VisitArgument(a gofire.Argument) error {
 		Name:     fmt.Sprintf("a%d", a.Index),
 		Ellipsis: a.Ellipsis,
 		Type:     typ,
+		Named:    named,
 	})
 	return nil
 }
@@ -85,19 +90,619 @@ func (d *Driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 		gname = g.Name
 		gdoc = g.Doc
 	}
-	name := fmt.Sprintf("%s%s", gname, f.Full)
-	doc := fmt.Sprintf("%s %s", gdoc, f.Doc)
+	// Nested flags paths are dotted and renamed flags could be dashed,
+	// so they need to be sanitized to produce valid names.
+	name := fmt.Sprintf("%s%s", gname, strings.NewReplacer(".", "_", "-", "_").Replace(f.Full))
+	doc := Doc(fmt.Sprintf("%s %s", gdoc, f.Doc), f)
 	var ref *generators.Reference
+	var repeatable bool
 	if g != nil {
-		ref = generators.NewReference(g.Type.Type(), gname, f.Full)
+		ref = generators.NewReference(g.Type.Type(), gname, strings.Split(f.Full, ".")...)
+		repeatable = g.Repeatable
 	}
+	typ, named := Underlying(f.Type)
 	d.params = append(d.params, generators.Parameter{
-		Name:  name,
-		Full:  f.Full,
-		Short: f.Short,
-		Type:  f.Type,
-		Doc:   doc,
-		Ref:   ref,
+		Name:       name,
+		Full:       f.Full,
+		Short:      f.Short,
+		Type:       typ,
+		Doc:        doc,
+		Ref:        ref,
+		Named:      named,
+		Repeatable: repeatable,
+		Env:        f.Env,
+		Required:   f.Required,
 	})
 	return nil
 }
+
+// Doc returns the flag documentation extended with the flag allowed values if they are defined,
+// with the environment variable name if it's provided, with the required marker if the flag is required
+// with the flag xor and with groups names if the flag belongs to them and with the implied flag name.
+func Doc(doc string, f gofire.Flag) string {
+	if choices := f.Choices(); len(choices) > 0 {
+		doc = fmt.Sprintf("%s (one of %s)", doc, strings.Join(choices, ", "))
+	}
+	if f.Env != "" {
+		doc = fmt.Sprintf("%s (env %s)", doc, f.Env)
+	}
+	if f.Required {
+		doc = fmt.Sprintf("%s (required)", doc)
+	}
+	if f.Xor != "" {
+		doc = fmt.Sprintf("%s (xor %s)", doc, f.Xor)
+	}
+	if f.With != "" {
+		doc = fmt.Sprintf("%s (with %s)", doc, f.With)
+	}
+	if f.Implies != "" {
+		doc = fmt.Sprintf("%s (implies %s)", doc, f.Implies)
+	}
+	return doc
+}
+
+// Enum produces the code that checks that the provided flag value v is one of the flag enum values,
+// the flags that weren't provided keep their defaults that are validated by the parser instead.
+// The changed defines the expression that checks whether the flag was provided.
+func Enum(flag, v, changed string, f gofire.Flag) string {
+	if len(f.Enum) == 0 {
+		return ""
+	}
+	var cases strings.Builder
+	for _, value := range f.Enum {
+		_, _ = fmt.Fprintf(&cases, "%q,", value)
+	}
+	return fmt.Sprintf(
+		`
+			if %s {
+				switch v := fmt.Sprint(%s); v {
+				case %s:
+				default:
+					return fmt.Errorf("flag %s value %%s is not one of %%s", v, %q)
+				}
+			}
+		`,
+		changed,
+		v,
+		strings.TrimSuffix(cases.String(), ","),
+		flag,
+		strings.Join(f.Enum, ", "),
+	)
+}
+
+// Constrained checks whether the flag has any value constraints.
+func Constrained(f gofire.Flag) bool {
+	return f.Min != "" || f.Max != "" || f.Pattern != "" || f.MinLen != "" || f.MaxLen != ""
+}
+
+// Constraints produces the code that checks that the provided flag value v satisfies the flag value constraints,
+// the flags that weren't provided keep their defaults that are validated by the parser instead.
+// The changed defines the expression that checks whether the flag was provided.
+func Constraints(flag, v, changed string, f gofire.Flag) string {
+	if !Constrained(f) {
+		return ""
+	}
+	var checks strings.Builder
+	if f.Min != "" {
+		_, _ = fmt.Fprintf(
+			&checks,
+			`if v < %s { return fmt.Errorf("flag %s value %%v is less than min %s", v) };`,
+			f.Min,
+			flag,
+			f.Min,
+		)
+	}
+	if f.Max != "" {
+		_, _ = fmt.Fprintf(
+			&checks,
+			`if v > %s { return fmt.Errorf("flag %s value %%v is greater than max %s", v) };`,
+			f.Max,
+			flag,
+			f.Max,
+		)
+	}
+	if f.Pattern != "" {
+		_, _ = fmt.Fprintf(
+			&checks,
+			`if !regexp.MustCompile(%q).MatchString(v) { return fmt.Errorf("flag %s value %%q doesn't match pattern %%s", v, %q) };`,
+			f.Pattern,
+			flag,
+			f.Pattern,
+		)
+	}
+	if f.MinLen != "" || f.MaxLen != "" {
+		length := "len(v)"
+		typ, _ := Underlying(f.Type)
+		if t, ok := typ.(gofire.TPtr); ok {
+			typ = t.ETyp
+		}
+		// String length is measured in runes the same way the parser measures it.
+		if typ.Kind() == gofire.String {
+			length = "utf8.RuneCountInString(v)"
+		}
+		_, _ = fmt.Fprintf(&checks, "l := %s;", length)
+		if f.MinLen != "" {
+			_, _ = fmt.Fprintf(
+				&checks,
+				`if l < %s { return fmt.Errorf("flag %s value length %%d is less than minlen %s", l) };`,
+				f.MinLen,
+				flag,
+				f.MinLen,
+			)
+		}
+		if f.MaxLen != "" {
+			_, _ = fmt.Fprintf(
+				&checks,
+				`if l > %s { return fmt.Errorf("flag %s value length %%d is greater than maxlen %s", l) };`,
+				f.MaxLen,
+				flag,
+				f.MaxLen,
+			)
+		}
+	}
+	return fmt.Sprintf(
+		`
+			if %s {
+				v := %s
+				%s
+			}
+		`,
+		changed,
+		v,
+		checks.String(),
+	)
+}
+
+// Optional produces the code that resets the optional pointer flag variable to nil if the flag wasn't provided
+// either explicitly or through its environment variable or configuration, so absent flags could be told apart
+// from the flags set to their default. The changed defines the expression that checks whether the flag was provided.
+func Optional(name, changed string, f gofire.Flag) string {
+	if !f.Optional {
+		return ""
+	}
+	return fmt.Sprintf(
+		`
+			if !%s {
+				%s = nil
+			}
+		`,
+		changed,
+		name,
+	)
+}
+
+// Envs produces the code that sets the flags that weren't provided explicitly from their environment
+// variables, so the flags are resolved in the order: the flag, the environment variable, the default.
+// The changed and set define functions that check whether the flag was provided and set the flag by name.
+func Envs(envs map[string]string, changed, set string) string {
+	if len(envs) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&list, "{%q, %q},", name, envs[name])
+	}
+	return fmt.Sprintf(
+		`
+			for _, fe := range [][2]string{%s} {
+				if v := os.Getenv(fe[1]); v != "" && !%s(fe[0]) {
+					if err := %s(fe[0], v); err != nil {
+						return fmt.Errorf("env %%s parse error: %%v", fe[1], err)
+					}
+				}
+			}
+		`,
+		list.String(),
+		changed,
+		set,
+	)
+}
+
+// Required produces the code that checks that all the required flags were provided, either explicitly
+// or through their environment variables or configuration, and reports all the missing flags at once.
+// The changed defines function that checks whether the flag was provided by name.
+func Required(required []string, changed string) string {
+	if len(required) == 0 {
+		return ""
+	}
+	sort.Strings(required)
+	var list strings.Builder
+	for _, name := range required {
+		_, _ = fmt.Fprintf(&list, "%q,", name)
+	}
+	return fmt.Sprintf(
+		`
+			{
+				var missing []string
+				for _, name := range []string{%s} {
+					if !%s(name) {
+						missing = append(missing, strconv.Quote(name))
+					}
+				}
+				if len(missing) > 0 {
+					return fmt.Errorf("required flag(s) %%s not set", strings.Join(missing, ", "))
+				}
+			}
+		`,
+		list.String(),
+		changed,
+	)
+}
+
+// Exclusive produces the code that checks that at most one flag of every xor group was provided.
+// The groups map the groups names to their flags names, the changed defines function that checks
+// whether the flag was provided by name.
+func Exclusive(groups map[string][]string, changed string) string {
+	return rule(
+		groups,
+		changed,
+		`
+			if len(set) > 1 {
+				return fmt.Errorf("flag(s) %s of group %s are mutually exclusive", strings.Join(set, ", "), group.name)
+			}
+		`,
+	)
+}
+
+// Together produces the code that checks that either all or none flags of every with group were provided.
+// The groups map the groups names to their flags names, the changed defines function that checks
+// whether the flag was provided by name.
+func Together(groups map[string][]string, changed string) string {
+	return rule(
+		groups,
+		changed,
+		`
+			if len(set) > 0 && len(missing) > 0 {
+				return fmt.Errorf(
+					"flag(s) %s of group %s not set, required together with %s",
+					strings.Join(missing, ", "),
+					group.name,
+					strings.Join(set, ", "),
+				)
+			}
+		`,
+	)
+}
+
+// Implied returns the implied flag name qualified the same way as the flag itself,
+// as the implied flag is referenced by its field name in the flag group.
+func Implied(f gofire.Flag, g *gofire.Group) string {
+	if f.Implies == "" || g == nil {
+		return f.Implies
+	}
+	return fmt.Sprintf("%s.%s", g.Name, f.Implies)
+}
+
+// Implies produces the code that checks that the implied flags were provided along with their implying flags.
+// The implies map the flags names to their implied flags names, the changed defines function that checks
+// whether the flag was provided by name.
+func Implies(implies map[string]string, changed string) string {
+	if len(implies) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(implies))
+	for name := range implies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&list, "{flag: %q, implied: %q},", name, implies[name])
+	}
+	return fmt.Sprintf(
+		`
+			for _, rule := range []struct{ flag, implied string }{ %s } {
+				if %s(rule.flag) && !%s(rule.implied) {
+					return fmt.Errorf("flag %%s requires flag %%s to be provided", rule.flag, rule.implied)
+				}
+			}
+		`,
+		list.String(),
+		changed,
+		changed,
+	)
+}
+
+// rule produces the code that splits the flags of every group into provided and missing
+// flags lists and then applies the check to them.
+func rule(groups map[string][]string, changed, check string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&list, "{%q, %#v},", name, groups[name])
+	}
+	return fmt.Sprintf(
+		`
+			for _, group := range []struct{ name string; flags []string }{%s} {
+				var set, missing []string
+				for _, name := range group.flags {
+					if %s(name) {
+						set = append(set, strconv.Quote(name))
+					} else {
+						missing = append(missing, strconv.Quote(name))
+					}
+				}
+				%s
+			}
+		`,
+		list.String(),
+		changed,
+		check,
+	)
+}
+
+// ConfigDoc and PrintConfigDoc define the documentation of the configuration flags.
+const (
+	ConfigDoc      = "path to the configuration file (json, yaml or toml)."
+	PrintConfigDoc = "print the effective configuration and exit."
+)
+
+// Failed returns the condition under which the command error is reported along with the usage,
+// printed output of commands with configuration is not a failure, so the usage isn't reported for it.
+func Failed(cmd gofire.Command) string {
+	if cmd.Config {
+		return "err != nil && !errors.Is(err, gofireoutput.ErrPrinted)"
+	}
+	return "err != nil"
+}
+
+// Config produces the code that loads the configuration file provided by the config flag
+// and sets the flags that weren't provided explicitly from the configuration values,
+// then prints the effective configuration and returns output printed error if the print config flag is provided.
+// The defined, changed and set define functions that check whether the flag exists, whether the flag
+// was provided and set the flag by name; the visit defines the code that fills values map with all flags values.
+func Config(defined, changed, set, visit string) string {
+	return fmt.Sprintf(
+		`
+			if _config != "" {
+				values, err := gofireconfig.Load(_config)
+				if err != nil {
+					return err
+				}
+				if err := gofireconfig.Apply(values, %s, %s, %s); err != nil {
+					return err
+				}
+			}
+			if _printConfig {
+				values := make(map[string]string)
+				%s
+				if err := gofireconfig.Print(os.Stdout, values); err != nil {
+					return err
+				}
+				return gofireoutput.ErrPrinted
+			}
+		`,
+		defined,
+		changed,
+		set,
+		visit,
+	)
+}
+
+// Repeats produces the code that counts repeatable group indexes provided in the flags,
+// so repeatable group flags could be registered for every index. The count is stored
+// in g{group}_n variable, rng defines range clause over the flags names as arg variable.
+// As every index is registered, negative indexes, indexes that exceed the number of arguments
+// and gaps between indexes are reported as errors instead.
+func Repeats(group, rng string) string {
+	return fmt.Sprintf(
+		`
+			g%[1]s_n := 0
+			g%[1]s_idx := make(map[int]bool)
+			for %[2]s {
+				arg = strings.TrimLeft(strings.SplitN(arg, "=", 2)[0], "-")
+				if !strings.HasPrefix(arg, "%[1]s.") {
+					continue
+				}
+				idx := strings.SplitN(strings.TrimPrefix(arg, "%[1]s."), ".", 2)[0]
+				i, err := strconv.Atoi(idx)
+				if err != nil {
+					continue
+				}
+				if i < 0 || i >= len(os.Args) {
+					return fmt.Errorf("flag %%s index %%d is out of range", arg, i)
+				}
+				g%[1]s_idx[i] = true
+				if i >= g%[1]s_n {
+					g%[1]s_n = i + 1
+				}
+			}
+			if len(g%[1]s_idx) != g%[1]s_n {
+				return fmt.Errorf("group %[1]s indexes have to be provided continuously from 0 to %%d", g%[1]s_n-1)
+			}
+		`,
+		group,
+		rng,
+	)
+}
+
+// Underlying unwraps named type or pointer to named type to its underlying type,
+// so drivers could process it as a regular type. Original named type is returned
+// as well, so generated code could convert the parsed value back to it.
+func Underlying(typ gofire.Typ) (gofire.Typ, gofire.Typ) {
+	switch t := typ.(type) {
+	case gofire.TNamed:
+		return t.ETyp, typ
+	case gofire.TPtr:
+		if tn, ok := t.ETyp.(gofire.TNamed); ok {
+			return gofire.TPtr{ETyp: tn.ETyp}, typ
+		}
+	}
+	return typ, nil
+}
+
+// Scalar checks whether the type is a primitive, a time or a text type,
+// so it could be parsed directly from a single command line value.
+func Scalar(typ gofire.Typ) bool {
+	switch typ.(type) {
+	case gofire.TPrimitive, gofire.TDuration, gofire.TTime, gofire.TText:
+		return true
+	default:
+		return false
+	}
+}
+
+// Verb returns the flag registration function name part for the type,
+// e.g. Int64 for flag.Int64Var or Duration for flag.DurationVar.
+func Verb(typ string) string {
+	return strings.Title(strings.TrimPrefix(typ, "time."))
+}
+
+// TimeDefault returns the quoted time default value formatted accordingly to the time layout,
+// so it could be used as a string flag default value.
+func TimeDefault(t gofire.TTime, val interface{}) string {
+	tv, ok := val.(time.Time)
+	if !ok || tv.IsZero() {
+		return `""`
+	}
+	return fmt.Sprintf("%q", tv.Format(t.TimeLayout()))
+}
+
+// Unmarshal returns the expression that parses the text into the text type variable either through
+// encoding.TextUnmarshaler, flag.Value, the parser function or the constants names, the expression yields an error.
+func Unmarshal(t gofire.TText, v, text string) string {
+	// Constants are matched by their names or by their literal values, so only the declared constants are accepted.
+	if len(t.Consts) > 0 {
+		var cases strings.Builder
+		for i, name := range t.Names() {
+			_, _ = fmt.Fprintf(&cases, "case %q: %s = %s;", name, v, t.Consts[i])
+		}
+		literal := `fmt.Sprintf("%d", c)`
+		if t.ETyp != nil && t.ETyp.Kind() == gofire.String {
+			literal = "string(c)"
+		}
+		return fmt.Sprintf(
+			`func() error {
+				switch %s {
+				%s
+				default:
+					for _, c := range []%s{%s} {
+						if %s == %s {
+							%s = c
+							return nil
+						}
+					}
+					return fmt.Errorf("%%s is not one of %%s", %s, %q)
+				}
+				return nil
+			}()`,
+			text,
+			cases.String(),
+			t.Typ,
+			strings.Join(t.Consts, ", "),
+			literal,
+			text,
+			v,
+			text,
+			strings.Join(t.Names(), ", "),
+		)
+	}
+	if t.Parser != "" {
+		return fmt.Sprintf("func() (err error) { %s, err = %s(%s); return }()", v, t.Parser, text)
+	}
+	if t.Value {
+		return fmt.Sprintf("%s.Set(%s)", v, text)
+	}
+	return fmt.Sprintf("%s.UnmarshalText([]byte(%s))", v, text)
+}
+
+// Usage returns the value formatted for usage help, time types values are formatted
+// the same way they are provided in the command line, other types use their format.
+func Usage(typ gofire.Typ, val interface{}) string {
+	switch t := typ.(type) {
+	case gofire.TDuration:
+		if d, ok := val.(time.Duration); ok {
+			return d.String()
+		}
+	case gofire.TTime:
+		return TimeDefault(t, val)
+	}
+	return typ.Format(val)
+}
+
+// Placeholder returns the argument placeholder for usage help and errors, the upper cased
+// argument name if it's provided or the argument index based name otherwise.
+func Placeholder(a gofire.Argument) string {
+	name := fmt.Sprintf("arg%d", a.Index)
+	if a.Name != "" {
+		name = strings.ToUpper(a.Name)
+	}
+	if a.Ellipsis {
+		name += "..."
+	}
+	return name
+}
+
+// ArgumentUsage returns the argument usage, optional argument is enclosed
+// in brackets along with its default value e.g. [LINES=10].
+func ArgumentUsage(a gofire.Argument, typ gofire.Typ) string {
+	if !a.Optional {
+		return Placeholder(a)
+	}
+	return fmt.Sprintf("[%s=%s]", Placeholder(a), Usage(typ, a.Default))
+}
+
+// ArgumentDoc returns the argument description for usage help.
+func ArgumentDoc(a gofire.Argument, typ gofire.Typ) string {
+	doc := strings.TrimSpace(fmt.Sprintf("%s %s %s", Placeholder(a), typ.Type(), a.Doc))
+	if a.Optional {
+		doc += fmt.Sprintf(" (default %s)", Usage(typ, a.Default))
+	}
+	return doc
+}
+
+// Fallback wraps the argument parsing code written to the buffer since the offset, so the optional
+// argument variable is set to its default value if the argument wasn't provided. The provided defines
+// the expression that checks whether the argument was provided, required arguments code is kept as is.
+func Fallback(buf *bytes.Buffer, offset int, provided, name string, typ gofire.Typ, a gofire.Argument) {
+	if !a.Optional {
+		return
+	}
+	code := string(buf.Bytes()[offset:])
+	buf.Truncate(offset)
+	dflt := fmt.Sprintf("%s = %s", name, typ.Format(a.Default))
+	// Text types defaults are kept as text and unmarshalled the same way as the provided values.
+	if t, ok := typ.(gofire.TText); ok {
+		dflt = fmt.Sprintf(
+			`if err := %s; err != nil { return fmt.Errorf("argument %%s default parse error: %%v", %q, err) }`,
+			Unmarshal(t, name, fmt.Sprintf("%q", a.Default)),
+			Placeholder(a),
+		)
+	}
+	_, _ = fmt.Fprintf(buf, "if %s { %s } else { %s }\n", provided, code, dflt)
+}
+
+// Manual returns the command description for usage help, either the long description or the doc
+// followed by the command aliases and examples if they are provided.
+func Manual(cmd gofire.Command) string {
+	var lines []string
+	switch {
+	case cmd.Long != "":
+		lines = append(lines, cmd.Long)
+	case cmd.Doc != "":
+		lines = append(lines, cmd.Doc)
+	}
+	if len(cmd.Aliases) > 0 {
+		lines = append(lines, "aliases: "+strings.Join(cmd.Aliases, ", "))
+	}
+	if len(cmd.Examples) > 0 {
+		lines = append(lines, "examples:")
+		// Note that tabs are used for alignment here as spaces are collapsed by generator.
+		for _, example := range cmd.Examples {
+			lines = append(lines, "\t"+example)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -26,7 +26,7 @@ func GoExec(gocmd string, params ...string) Action {
 
 type Action func(context.Context, string) (string, error)
 
-func (a Action) RunOnTest(ctx context.Context, name generators.DriverName, dir, pckg, function string) (string, error) {
+func (a Action) RunOnTest(ctx context.Context, name generators.DriverName, dir, pckg string, functions []string, opts ...cmd.Option) (string, error) {
 	d, err := ioutil.TempDir("", "*")
 	if err != nil {
 		return "", err
@@ -35,7 +35,12 @@ func (a Action) RunOnTest(ctx context.Context, name generators.DriverName, dir,
 	if err := a.copy(dir, d); err != nil {
 		return "", err
 	}
-	if _, err := cmd.Run(ctx, name, d, pckg, function); err != nil {
+	// In case no functions are provided run generation in package mode.
+	if len(functions) == 0 {
+		if _, err := cmd.RunPackage(ctx, name, d, pckg, opts...); err != nil {
+			return "", err
+		}
+	} else if _, err := cmd.Run(ctx, name, d, pckg, functions, opts...); err != nil {
 		return "", err
 	}
 	return a(ctx, d)
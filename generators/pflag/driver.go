@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/config"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -20,30 +21,49 @@ type driver struct {
 	preParse   bytes.Buffer
 	postParse  bytes.Buffer
 	usageList  []string
+	argList    []string
+	argDocs    []string
 	printList  []string
 	shortNames map[string]bool
+	repeats    map[string]bool
+	envs       map[string]string
+	required   []string
+	xor        map[string][]string
+	with       map[string][]string
+	implies    map[string]string
 }
 
 func (d driver) Output(cmd gofire.Command) (string, error) {
 	var buf bytes.Buffer
-	if _, err := buf.WriteString(
+	if _, err := fmt.Fprintf(
+		&buf,
 		`
+			// reset command line flag set so the command could be run multiple times.
+			pflag.CommandLine = pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
 			defer func() {
-				if err != nil {
+				if %s {
 					pflag.Usage()
 				}
 			}()
 		`,
+		internal.Failed(cmd),
 	); err != nil {
 		return "", err
 	}
-	if _, err := buf.Write(d.preParse.Bytes()); err != nil {
-		return "", err
+	if cmd.Config {
+		d.usageList = append(d.usageList, fmt.Sprintf("--%s=%q", config.Flag, ""), fmt.Sprintf("--%s=false", config.PrintFlag))
+		d.printList = append(
+			d.printList,
+			fmt.Sprintf("--%s  string %s (default %q) ", config.Flag, internal.ConfigDoc, ""),
+			fmt.Sprintf("--%s  bool %s (default false) ", config.PrintFlag, internal.PrintConfigDoc),
+		)
 	}
 	sort.Strings(d.usageList)
 	sort.Strings(d.printList)
-	u := strings.Join(d.usageList, " ")
-	p := strings.Join(d.printList, " ")
+	// Arguments are kept in their positional order after the flags.
+	u := strings.Join(append(d.usageList, d.argList...), " ")
+	p := strings.Join(append(d.printList, d.argDocs...), " ")
+	// Usage is defined before any flag is registered, so errors of the flags registration are reported with it.
 	if _, err := fmt.Fprintf(
 		&buf,
 		`
@@ -60,15 +80,69 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 				}
 			}
 		`,
-		cmd.Doc,
-		fmt.Sprintf("%s %s [--help -h]", cmd.Function, u),
+		internal.Manual(cmd),
+		fmt.Sprintf("%s %s [--help -h]", cmd.Title(), u),
 		fmt.Sprintf("%s, %s", cmd.Definition, p),
 	); err != nil {
 		return "", err
 	}
+	if _, err := buf.Write(d.preParse.Bytes()); err != nil {
+		return "", err
+	}
+	if cmd.Config {
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				var _config string
+				pflag.StringVarP(&_config, %q, "", "", %q)
+				var _printConfig bool
+				pflag.BoolVarP(&_printConfig, %q, "", false, %q)
+			`,
+			config.Flag,
+			internal.ConfigDoc,
+			config.PrintFlag,
+			internal.PrintConfigDoc,
+		); err != nil {
+			return "", err
+		}
+	}
 	if _, err := buf.WriteString("pflag.Parse()"); err != nil {
 		return "", err
 	}
+	if _, err := buf.WriteString(internal.Envs(d.envs, "pflag.CommandLine.Changed", "pflag.Set")); err != nil {
+		return "", err
+	}
+	if cmd.Config {
+		if _, err := buf.WriteString(internal.Config(
+			"func(name string) bool { return pflag.Lookup(name) != nil }",
+			"pflag.CommandLine.Changed",
+			"pflag.Set",
+			`
+				pflag.VisitAll(func(f *pflag.Flag) {
+					// slice flags are printed the same way they are loaded, as comma separated values.
+					if sv, ok := f.Value.(pflag.SliceValue); ok {
+						values[f.Name] = gofireconfig.Join(sv.GetSlice())
+						return
+					}
+					values[f.Name] = f.Value.String()
+				})
+			`,
+		)); err != nil {
+			return "", err
+		}
+	}
+	if _, err := buf.WriteString(internal.Required(d.required, "pflag.CommandLine.Changed")); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Exclusive(d.xor, "pflag.CommandLine.Changed")); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Together(d.with, "pflag.CommandLine.Changed")); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Implies(d.implies, "pflag.CommandLine.Changed")); err != nil {
+		return "", err
+	}
 	if _, err := buf.Write(d.postParse.Bytes()); err != nil {
 		return "", err
 	}
@@ -80,8 +154,16 @@ func (d *driver) Reset() error {
 	d.preParse.Reset()
 	d.postParse.Reset()
 	d.usageList = nil
+	d.argList = nil
+	d.argDocs = nil
 	d.printList = nil
 	d.shortNames = make(map[string]bool)
+	d.repeats = make(map[string]bool)
+	d.envs = make(map[string]string)
+	d.required = nil
+	d.xor = make(map[string][]string)
+	d.with = make(map[string][]string)
+	d.implies = make(map[string]string)
 	return nil
 }
 
@@ -91,18 +173,22 @@ func (driver) Name() generators.DriverName {
 
 func (d driver) Imports() []string {
 	return []string{
+		`"errors"`,
 		`"fmt"`,
+		`"os"`,
 		`"strconv"`,
+		`"strings"`,
+		`"time"`,
 		`"github.com/spf13/pflag"`,
+		`gofireconfig "github.com/1pkg/gofire/config"`,
 	}
 }
 
 func (d *driver) VisitArgument(a gofire.Argument) error {
 	_ = d.Driver.VisitArgument(a)
 	p := d.Last()
-	typ := a.Type
-	tp, ok := typ.(gofire.TPrimitive)
-	if !ok {
+	typ, _ := internal.Underlying(a.Type)
+	if !internal.Scalar(typ) {
 		return fmt.Errorf(
 			"driver %s: non primitive argument types are not supported, got an argument %s %s",
 			d.Name(),
@@ -110,13 +196,18 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 			typ.Type(),
 		)
 	}
-	if err := d.argument(p.Name, a.Index, tp, p.Ellipsis); err != nil {
+	offset := d.postParse.Len()
+	if err := d.argument(p.Name, internal.Placeholder(a), a.Index, typ, p.Ellipsis); err != nil {
 		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
 	}
+	internal.Fallback(&d.postParse, offset, fmt.Sprintf("pflag.NArg() > %d", a.Index), p.Name, typ, a)
+	d.argList = append(d.argList, internal.ArgumentUsage(a, typ))
+	d.argDocs = append(d.argDocs, internal.ArgumentDoc(a, typ))
 	return nil
 }
 
 func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
+	f.Implies = internal.Implied(f, g)
 	_ = d.Driver.VisitFlag(f, g)
 	p := d.Last()
 	typ := p.Type
@@ -132,34 +223,84 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	case 0:
 	case 1:
 		if d.shortNames[p.Short] {
-			return fmt.Errorf("driver %s: short flag name %q has been already registred", d.Name(), p.Short)
+			return fmt.Errorf("driver %s: short flag name %q has been already registered", d.Name(), p.Short)
 		}
 		d.shortNames[p.Short] = true
 	default:
 		return fmt.Errorf("driver %s: short flag name %q is not supported", d.Name(), p.Short)
 	}
-	if err := d.flag(p.Name, full, p.Short, typ, ptr, f.Default, f.Doc, f.Deprecated, f.Hidden); err != nil {
+	if p.Repeatable {
+		if p.Short != "" {
+			return fmt.Errorf("driver %s: short flag name %q is not supported for repeatable groups", d.Name(), p.Short)
+		}
+		if p.Required {
+			return fmt.Errorf("driver %s: required flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if len(f.Enum) > 0 {
+			return fmt.Errorf("driver %s: enum flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if internal.Constrained(f) {
+			return fmt.Errorf("driver %s: constrained flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if f.Xor != "" || f.With != "" || f.Implies != "" {
+			return fmt.Errorf("driver %s: xor, with and implies flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if f.Optional {
+			return fmt.Errorf("driver %s: optional flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if err := d.repeatable(p.Name, p.Ref.Group(), p.Full, typ, ptr, f.Default, f.Doc, f.Deprecated, f.Hidden); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+		return nil
+	}
+	if err := d.flag(p.Name, full, p.Short, typ, ptr, f.Default, internal.Doc(f.Doc, f), f.Deprecated, f.Hidden); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	changed := fmt.Sprintf("pflag.CommandLine.Changed(%q)", full)
+	if _, err := d.postParse.WriteString(internal.Enum(full, p.Name+"_", changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.postParse.WriteString(internal.Constraints(full, p.Name+"_", changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.postParse.WriteString(internal.Optional(p.Name, changed, f)); err != nil {
 		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
 	}
+	if p.Env != "" {
+		d.envs[full] = p.Env
+	}
+	if p.Required {
+		d.required = append(d.required, full)
+	}
+	if f.Xor != "" {
+		d.xor[f.Xor] = append(d.xor[f.Xor], full)
+	}
+	if f.With != "" {
+		d.with[f.With] = append(d.with[f.With], full)
+	}
+	if f.Implies != "" {
+		d.implies[full] = f.Implies
+	}
 	return nil
 }
 
-func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellipsis bool) error {
+func (d *driver) argument(name, label string, index uint64, t gofire.Typ, ellipsis bool) error {
 	k := t.Kind()
 	if ellipsis {
 		switch k {
 		case gofire.Bool:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < pflag.NArg(); i++ {
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
 						v, err := strconv.ParseBool(pflag.Arg(i))
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, v)
 					}
 				`,
 				index,
+				label,
 				name,
 				name,
 			); err != nil {
@@ -168,15 +309,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < pflag.NArg(); i++ {
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
 						v, err := strconv.ParseInt(pflag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -187,15 +329,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < pflag.NArg(); i++ {
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
 						v, err := strconv.ParseUint(pflag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -206,15 +349,16 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 		case gofire.Float32, gofire.Float64:
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
-					for i := %d; i < pflag.NArg(); i++ {
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
 						v, err := strconv.ParseFloat(pflag.Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = append(%s, %s(v))
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				name,
@@ -235,6 +379,63 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			); err != nil {
 				return err
 			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
+						v, err := time.ParseDuration(pflag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
+						v, err := time.Parse(%q, pflag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					for i, arg := %d, %q; i < pflag.NArg(); i++ {
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = append(%s, v)
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "pflag.Arg(i)"),
+				name,
+				name,
+			); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf(
 				"ellipsis type %s is not supported for an argument %s",
@@ -248,18 +449,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{	
-						const i = %d
+						const i, arg = %d, %q
 						if pflag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseBool(pflag.Arg(i))
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = v
 					}
 				`,
 				index,
+				label,
 				name,
 			); err != nil {
 				return err
@@ -268,18 +470,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if pflag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseInt(pflag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -290,18 +493,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if pflag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseUint(pflag.Arg(i), 10, %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -312,18 +516,19 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if pflag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						v, err := strconv.ParseFloat(pflag.Arg(i), %d)
 						if err != nil {
-							return fmt.Errorf("argument %%d-th parse error: %%v", i, err)
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
 						}
 						%s = %s(v)
 					}
 				`,
 				index,
+				label,
 				k.Base(),
 				name,
 				k.Type(),
@@ -334,14 +539,81 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			if _, err := fmt.Fprintf(&d.postParse,
 				`
 					{
-						const i = %d
+						const i, arg = %d, %q
 						if pflag.NArg() <= i {
-							return fmt.Errorf("argument %%d-th is required", i)
+							return fmt.Errorf("argument %%s is required", arg)
 						}
 						%s = pflag.Arg(i)
 					}
 				`,
 				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Duration:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if pflag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.ParseDuration(pflag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Time:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if pflag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						v, err := time.Parse(%q, pflag.Arg(i))
+						if err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.(gofire.TTime).TimeLayout(),
+				name,
+			); err != nil {
+				return err
+			}
+		case gofire.Text:
+			if _, err := fmt.Fprintf(&d.postParse,
+				`
+					{
+						const i, arg = %d, %q
+						if pflag.NArg() <= i {
+							return fmt.Errorf("argument %%s is required", arg)
+						}
+						var v %s
+						if err := %s; err != nil {
+							return fmt.Errorf("argument %%s parse error: %%v", arg, err)
+						}
+						%s = v
+					}
+				`,
+				index,
+				label,
+				t.Type(),
+				internal.Unmarshal(t.(gofire.TText), "v", "pflag.Arg(i)"),
 				name,
 			); err != nil {
 				return err
@@ -354,12 +626,6 @@ func (d *driver) argument(name string, index uint64, t gofire.TPrimitive, ellips
 			)
 		}
 	}
-	symb := "arg"
-	if ellipsis {
-		symb += "..."
-	}
-	d.usageList = append(d.usageList, fmt.Sprintf("arg%d", index))
-	d.printList = append(d.printList, fmt.Sprintf("%s %d %s", symb, index, t.Type()))
 	return nil
 }
 
@@ -379,6 +645,8 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 			fallthrough
 		case gofire.Float32, gofire.Float64:
 			fallthrough
+		case gofire.Duration:
+			fallthrough
 		case gofire.String:
 			if _, err := fmt.Fprintf(&d.preParse,
 				`
@@ -387,7 +655,7 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 				`,
 				name,
 				ts.Type(),
-				strings.Title(etyp.Type()),
+				internal.Verb(etyp.Type()),
 				name,
 				full,
 				short,
@@ -422,6 +690,8 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 		fallthrough
 	case gofire.Float32, gofire.Float64:
 		fallthrough
+	case gofire.Duration:
+		fallthrough
 	case gofire.String:
 		if _, err := fmt.Fprintf(&d.preParse,
 			`
@@ -430,7 +700,7 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 			`,
 			name,
 			t.Type(),
-			strings.Title(t.Type()),
+			internal.Verb(t.Type()),
 			name,
 			full,
 			short,
@@ -450,6 +720,86 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 		); err != nil {
 			return err
 		}
+	case gofire.Time:
+		// Time flags are parsed from their string representation accordingly to the layout.
+		tt := t.(gofire.TTime)
+		if _, err := fmt.Fprintf(&d.preParse,
+			`
+				var %s_ string
+				pflag.StringVarP(&%s_, %q, %q, %s, %q)
+			`,
+			name,
+			name,
+			full,
+			short,
+			internal.TimeDefault(tt, val),
+			doc,
+		); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(
+			&d.postParse,
+			`
+				{
+					var v time.Time
+					if %s_ != "" {
+						t, err := time.Parse(%q, %s_)
+						if err != nil {
+							return fmt.Errorf("flag %s parse error: %%v", err)
+						}
+						v = t
+					}
+					%s = %sv
+				}
+			`,
+			name,
+			tt.TimeLayout(),
+			name,
+			full,
+			name,
+			amp,
+		); err != nil {
+			return err
+		}
+	case gofire.Text:
+		// Text flags are parsed from their raw string representation through the type interface.
+		tt := t.(gofire.TText)
+		if _, err := fmt.Fprintf(&d.preParse,
+			`
+				var %s_ string
+				pflag.StringVarP(&%s_, %q, %q, %s, %q)
+			`,
+			name,
+			name,
+			full,
+			short,
+			tt.Format(val),
+			doc,
+		); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(
+			&d.postParse,
+			`
+				{
+					var v %s
+					if %s_ != "" {
+						if err := %s; err != nil {
+							return fmt.Errorf("flag %s parse error: %%v", err)
+						}
+					}
+					%s = %sv
+				}
+			`,
+			tt.Type(),
+			name,
+			internal.Unmarshal(tt, "v", name+"_"),
+			full,
+			name,
+			amp,
+		); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf(
 			"type %s is not supported for a flag %s",
@@ -494,16 +844,109 @@ func (d *driver) flag(name, full, short string, t gofire.Typ, ptr bool, val inte
 	if deprecated {
 		pdeprecated = "(DEPRECATED)"
 	}
-	u := fmt.Sprintf("--%s=%s", full, t.Format(val))
+	u := fmt.Sprintf("--%s=%s", full, internal.Usage(t, val))
 	var pshort string
 	if short != "" {
-		u += " " + fmt.Sprintf("-%s=%s", short, t.Format(val))
+		u += " " + fmt.Sprintf("-%s=%s", short, internal.Usage(t, val))
 		pshort = fmt.Sprintf("-%s", short)
 	}
 	d.usageList = append(d.usageList, u)
 	d.printList = append(
 		d.printList,
-		fmt.Sprintf("--%s %s %s %s (default %s) %s", full, pshort, t.Type(), doc, t.Format(val), pdeprecated),
+		fmt.Sprintf("--%s %s %s %s (default %s) %s", full, pshort, t.Type(), doc, internal.Usage(t, val), pdeprecated),
+	)
+	return nil
+}
+
+// repeatable registers the flag for every repeatable group index provided in the command line,
+// flags are named after the group, the index and the field e.g. --servers.0.host.
+func (d *driver) repeatable(name, group, field string, t gofire.Typ, ptr bool, val interface{}, doc string, deprecated, hidden bool) error {
+	full := fmt.Sprintf("%s.N.%s", group, field)
+	var verb string
+	switch t.Kind() {
+	case gofire.Slice:
+		etyp := t.(gofire.TSlice).ETyp
+		switch etyp.Kind() {
+		case gofire.Bool, gofire.Int32, gofire.Int64, gofire.Float32, gofire.Float64, gofire.Duration, gofire.String:
+			verb = internal.Verb(etyp.Type()) + "Slice"
+		default:
+			return fmt.Errorf(
+				"type %s is not supported for a flag %s",
+				t.Type(),
+				full,
+			)
+		}
+	case gofire.Bool:
+		fallthrough
+	case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
+		fallthrough
+	case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
+		fallthrough
+	case gofire.Float32, gofire.Float64:
+		fallthrough
+	case gofire.Duration:
+		fallthrough
+	case gofire.String:
+		verb = internal.Verb(t.Type())
+	default:
+		return fmt.Errorf(
+			"type %s is not supported for a flag %s",
+			t.Type(),
+			full,
+		)
+	}
+	// Count the group indexes only once for all the group flags.
+	if !d.repeats[group] {
+		if _, err := d.preParse.WriteString(internal.Repeats(group, "_, arg := range os.Args[1:]")); err != nil {
+			return err
+		}
+		d.repeats[group] = true
+	}
+	typ, ref, alloc := t.Type(), fmt.Sprintf("&%s[i]", name), ""
+	if ptr {
+		typ, ref, alloc = "*"+t.Type(), fmt.Sprintf("%s[i]", name), fmt.Sprintf("%s[i] = new(%s)", name, t.Type())
+	}
+	var marks string
+	if deprecated {
+		marks += fmt.Sprintf(`pflag.CommandLine.MarkDeprecated(full, "deprecated: %s")`+"\n", doc)
+	}
+	if hidden {
+		marks += `pflag.CommandLine.MarkHidden(full)`
+	}
+	if _, err := fmt.Fprintf(&d.preParse,
+		`
+			%[1]s = make([]%[2]s, g%[3]s_n)
+			for i := range %[1]s {
+				full := fmt.Sprintf("%[3]s.%%d.%[4]s", i)
+				%[5]s
+				pflag.%[6]sVarP(%[7]s, full, "", %[8]s, %[9]q)
+				%[10]s
+			}
+		`,
+		name,
+		typ,
+		group,
+		field,
+		alloc,
+		verb,
+		ref,
+		t.Format(val),
+		doc,
+		marks,
+	); err != nil {
+		return err
+	}
+	if hidden {
+		return nil
+	}
+	var pdeprecated string
+	if deprecated {
+		pdeprecated = "(DEPRECATED)"
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, internal.Usage(t, val)))
+	d.printList = append(
+		d.printList,
+		fmt.Sprintf("--%s %s %s (default %s) %s", full, t.Type(), doc, internal.Usage(t, val), pdeprecated),
 	)
 	return nil
 }
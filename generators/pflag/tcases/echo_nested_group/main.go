@@ -0,0 +1,27 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type tls struct {
+	// certificate path.
+	cert string
+}
+
+type store struct {
+	host string `gofire:"default=localhost,short=H"`
+	tls  tls
+}
+
+// config holds config options.
+type config struct {
+	db, cache store
+}
+
+// echo documentation string.
+func echo(cfg config) {
+	fmt.Println(cfg.db.host, cfg.db.tls.cert, cfg.cache.host)
+}
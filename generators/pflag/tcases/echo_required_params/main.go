@@ -0,0 +1,21 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type db struct {
+	// database host.
+	Host string `gofire:"required"`
+	// database user.
+	User string `gofire:"required"`
+	// database port.
+	Port int `gofire:"default=5432"`
+}
+
+// echo documentation string.
+func echo(conn db) {
+	fmt.Println(conn.Host, conn.User, conn.Port)
+}
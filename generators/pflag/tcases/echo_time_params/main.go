@@ -0,0 +1,20 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type retry struct {
+	// retry interval.
+	Interval time.Duration `gofire:"default=30s"`
+	// retry deadline.
+	Until time.Time `gofire:"layout=2006-01-02,default=2021-01-02"`
+}
+
+// echo documentation string.
+func echo(timeout *time.Duration, since time.Time, opt retry) {
+	fmt.Println(*timeout, since.Format(time.RFC3339), opt.Interval, opt.Until.Format("2006-01-02"))
+}
@@ -6,38 +6,86 @@ import (
 	"strings"
 
 	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/config"
+	"github.com/1pkg/gofire/output"
 )
 
 // proxy defines data object proxy for generator.
 type proxy struct {
 	driver  Driver
 	command gofire.Command
+	main    bool
+	chained bool
 }
 
 // proxify creates new safe data object proxy.
-func proxify(driver Driver, cmd gofire.Command) (interface{}, error) {
+func proxify(driver Driver, cmd gofire.Command, main, chained bool) (interface{}, error) {
 	if _, err := driver.Output(cmd); err != nil {
 		return nil, err
 	}
-	return proxy{driver: driver, command: cmd}, nil
+	return proxy{driver: driver, command: cmd, main: main, chained: chained}, nil
 }
 
 func (p proxy) Package() string {
 	return p.command.Package
 }
 
+func (p proxy) Main() bool {
+	return p.main
+}
+
 func (p proxy) Function() string {
-	return "Command" + strings.Title(p.command.Function) + strings.Title(string(p.driver.Name()))
+	// Command names could be kebab-cased, so they are converted to camel case first.
+	name := strings.ReplaceAll(strings.Title(strings.ReplaceAll(p.command.Title(), "-", " ")), " ", "")
+	return "Command" + strings.Title(p.receiver()) + name + strings.Title(string(p.driver.Name()))
+}
+
+func (p proxy) Signature() string {
+	// chained method receives its receiver from the previous command result.
+	if recv := p.command.Receiver; recv != nil && p.chained {
+		return fmt.Sprintf("ctx context.Context, g%s %s", recv.Name, recv.Type.Type())
+	}
+	return "ctx context.Context"
 }
 
 func (p proxy) Doc() string {
+	if recv := p.receiver(); recv != "" {
+		return fmt.Sprintf("// %s is autogenerated cli interface for %s.%s method.", p.Function(), recv, p.command.Function)
+	}
 	return fmt.Sprintf("// %s is autogenerated cli interface for %s function.", p.Function(), p.command.Function)
 }
 
 func (p proxy) Import() string {
 	imports := append(p.driver.Imports(), `"context"`)
+	// types declared in other packages require their packages to be imported.
+	params := p.command.Parameters
+	if recv := p.command.Receiver; recv != nil {
+		params = append([]gofire.Parameter{*recv}, params...)
+	}
+	for _, param := range params {
+		switch tp := param.(type) {
+		case gofire.Group:
+			imports = append(imports, timports(tp.Type)...)
+			for _, f := range tp.Flags {
+				imports = append(imports, timports(f.Type)...)
+			}
+		case gofire.Placeholder:
+			imports = append(imports, timports(tp.Type)...)
+		case gofire.Argument:
+			imports = append(imports, timports(tp.Type)...)
+		case gofire.Flag:
+			imports = append(imports, timports(tp.Type)...)
+		}
+	}
+	// the same package could be imported by multiple types, so deduplicate imports.
 	sort.Strings(imports)
-	return strings.Join(imports, "\n")
+	uniq := imports[:0]
+	for i, imp := range imports {
+		if i == 0 || imp != imports[i-1] {
+			uniq = append(uniq, imp)
+		}
+	}
+	return strings.Join(uniq, "\n")
 }
 
 func (p proxy) Return() string {
@@ -72,13 +120,23 @@ func (p proxy) Vars() string {
 	vars := make([]string, 0, len(p.driver.Parameters()))
 	groups := make(map[string]bool)
 	for _, p := range p.driver.Parameters() {
-		vars = append(vars, fmt.Sprintf("var %s %s", p.Name, p.Type.Type()))
+		// repeatable group params and vars hold slices of values.
+		var slice string
+		if p.Repeatable {
+			slice = "[]"
+		}
+		vars = append(vars, fmt.Sprintf("var %s %s%s", p.Name, slice, p.Type.Type()))
 		// for each struct group generate separate var too.
 		if g := p.Ref.Group(); g != "" && !groups[g] {
-			vars = append(vars, fmt.Sprintf("var g%s %s", g, p.Ref.Type()))
+			vars = append(vars, fmt.Sprintf("var g%s %s%s", g, slice, p.Ref.Type()))
 			groups[g] = true
 		}
 	}
+	// receiver group var should be always defined even if it has no flags,
+	// unless it's chained method receiver which is provided as a param.
+	if recv := p.command.Receiver; recv != nil && !p.chained && !groups[recv.Name] {
+		vars = append(vars, fmt.Sprintf("var g%s %s", recv.Name, recv.Type.Type()))
+	}
 	return strings.Join(vars, "\n")
 }
 
@@ -90,9 +148,28 @@ func (p proxy) Body() string {
 func (p proxy) Groups() string {
 	// collect all group assigns and append them to generated body.
 	var gassigns []string
+	groups := make(map[string]bool)
 	for _, p := range p.driver.Parameters() {
-		if p.Ref != nil {
-			gassigns = append(gassigns, fmt.Sprintf("g%s=%s", p.Ref.Untyped(), p.Name))
+		switch {
+		case p.Ref == nil:
+		case p.Repeatable:
+			// repeatable group is sized after its first param and assigned index by index.
+			g := p.Ref.Group()
+			if !groups[g] {
+				gassigns = append(gassigns, fmt.Sprintf("g%s = make([]%s, len(%s))", g, p.Ref.Type(), p.Name))
+				groups[g] = true
+			}
+			name := p.Name
+			p.Name = fmt.Sprintf("%s[i]", name)
+			gassigns = append(gassigns, fmt.Sprintf(
+				"for i := range %s { g%s[i].%s=%s }",
+				name,
+				g,
+				p.Ref.Field(),
+				convert(p),
+			))
+		default:
+			gassigns = append(gassigns, fmt.Sprintf("g%s=%s", p.Ref.Untyped(), convert(p)))
 		}
 	}
 	return strings.Join(gassigns, "\n")
@@ -102,6 +179,12 @@ func (p proxy) Call() string {
 	// define call expression context param aware template.
 	parameters := make([]string, 0, len(p.driver.Parameters()))
 	groups := make(map[string]bool)
+	function := p.command.Function
+	// receiver group is not a call parameter, but a call operand.
+	if recv := p.command.Receiver; recv != nil {
+		groups[recv.Name] = true
+		function = fmt.Sprintf("g%s.%s", recv.Name, function)
+	}
 	for _, p := range p.driver.Parameters() {
 		if p.Ref != nil {
 			if groups[p.Ref.Group()] {
@@ -111,7 +194,7 @@ func (p proxy) Call() string {
 			groups[p.Ref.Group()] = true
 			continue
 		}
-		name := p.Name
+		name := convert(p)
 		if p.Ellipsis {
 			name = fmt.Sprintf("%s...", name)
 		}
@@ -119,9 +202,9 @@ func (p proxy) Call() string {
 	}
 	var call string
 	if p.command.Context {
-		call = fmt.Sprintf("%s(ctx, %s)", p.command.Function, strings.Join(parameters, ", "))
+		call = fmt.Sprintf("%s(ctx, %s)", function, strings.Join(parameters, ", "))
 	} else {
-		call = fmt.Sprintf("%s(%s)", p.command.Function, strings.Join(parameters, ", "))
+		call = fmt.Sprintf("%s(%s)", function, strings.Join(parameters, ", "))
 	}
 	// collect all return call signature param namep.
 	rnames := make([]string, 0, len(p.command.Results))
@@ -134,3 +217,202 @@ func (p proxy) Call() string {
 	}
 	return call
 }
+
+func (p proxy) Output() bool {
+	// the output format is only needed if any results are printed.
+	if printable(p.command) {
+		return true
+	}
+	for _, link := range p.command.Chain {
+		if printable(link) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p proxy) Config() bool {
+	return p.command.Config
+}
+
+func (p proxy) Printed() bool {
+	return Printed(p.command)
+}
+
+func (p proxy) Print() string {
+	rnames := make([]string, 0, len(p.command.Results))
+	for i := range p.command.Results {
+		rnames = append(rnames, fmt.Sprintf("o%d", i))
+	}
+	return printing(p.command, rnames, exit)
+}
+
+func (p proxy) Chain() []rcommand {
+	links := make([]rcommand, 0, len(p.command.Chain))
+	for _, link := range p.command.Chain {
+		links = append(links, rcommand{
+			Name: names(link),
+			Call: call(link, fmt.Sprintf("%s(ctx, o0)", proxy{driver: p.driver, command: link}.Function()), exit),
+		})
+	}
+	return links
+}
+
+// exit reports the error and terminates the main entrypoint.
+const exit = "fmt.Println(%s)\nos.Exit(2)"
+
+// call returns the command call code that assigns the command results to temporary variables
+// and prints them, the results are ignored except error if nothing is printed.
+func call(cmd gofire.Command, expr, fail string) string {
+	rnames := make([]string, 0, len(cmd.Results)+1)
+	for i := range cmd.Results {
+		rnames = append(rnames, fmt.Sprintf("r%d", i))
+	}
+	code := printing(cmd, rnames, fail)
+	if code == "" {
+		return discard(cmd, expr)
+	}
+	vars := make([]string, 0, len(cmd.Results))
+	for i, typ := range cmd.Results {
+		vars = append(vars, fmt.Sprintf("var %s %s", rnames[i], typ))
+	}
+	return fmt.Sprintf(
+		"%s\n%s = %s\nif err == nil {\n%s\n}",
+		strings.Join(vars, "\n"),
+		strings.Join(append(rnames, "err"), ", "),
+		expr,
+		code,
+	)
+}
+
+// discard returns the command call code that ignores all the command results except error.
+func discard(cmd gofire.Command, expr string) string {
+	rnames := make([]string, 0, len(cmd.Results)+1)
+	for range cmd.Results {
+		rnames = append(rnames, "_")
+	}
+	return fmt.Sprintf("%s = %s", strings.Join(append(rnames, "err"), ", "), expr)
+}
+
+// printing returns the code that prints the command results in the output format unless the command is silent,
+// error results are not printed, but reported through the fail code instead if they are not nil.
+func printing(cmd gofire.Command, rnames []string, fail string) string {
+	var code, values []string
+	for i, typ := range cmd.Results {
+		if typ == "error" {
+			code = append(code, fmt.Sprintf("if %s != nil {\n%s\n}", rnames[i], fmt.Sprintf(fail, rnames[i])))
+			continue
+		}
+		values = append(values, rnames[i])
+	}
+	if printable(cmd) {
+		code = append(code, fmt.Sprintf(
+			"if err := gofireoutput.Print(os.Stdout, format, %s); err != nil {\n%s\n}",
+			strings.Join(values, ", "),
+			fmt.Sprintf(fail, "err"),
+		))
+	}
+	return strings.Join(code, "\n")
+}
+
+// printable reports whether the command prints any of its results.
+func printable(cmd gofire.Command) bool {
+	if cmd.Silent {
+		return false
+	}
+	for _, typ := range cmd.Results {
+		if typ != "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// Printed checks whether the command main entrypoint prints anything besides errors, either the results
+// or the effective configuration. Only such commands report their own printed output with the output printed error,
+// so the main entrypoint treats it neither as a failure nor as the results.
+func Printed(cmd gofire.Command) bool {
+	if cmd.Config || printable(cmd) {
+		return true
+	}
+	for _, link := range cmd.Chain {
+		if printable(link) {
+			return true
+		}
+	}
+	return false
+}
+
+// collide checks that none of the commands flags is named as the options added by the generator itself,
+// either the output format option that is extracted from the arguments before any command could see them
+// if the results are printed or the configuration options that are registered along with commands flags.
+func collide(printed bool, cmds ...gofire.Command) error {
+	for _, cmd := range cmds {
+		for _, p := range cmd.Parameters {
+			f, ok := p.(gofire.Flag)
+			if !ok {
+				continue
+			}
+			if printed && f.Full == output.Flag {
+				return fmt.Errorf("flag %s of command %s collides with the output format option", f.Full, cmd.Title())
+			}
+			if cmd.Config && (f.Full == config.Flag || f.Full == config.PrintFlag) {
+				return fmt.Errorf("flag %s of command %s collides with the configuration option", f.Full, cmd.Title())
+			}
+		}
+	}
+	return nil
+}
+
+// names returns the command name and aliases as a list of quoted cases.
+func names(cmd gofire.Command) string {
+	names := []string{fmt.Sprintf("%q", cmd.Title())}
+	for _, alias := range cmd.Aliases {
+		names = append(names, fmt.Sprintf("%q", alias))
+	}
+	return strings.Join(names, ", ")
+}
+
+// timports returns imports required by the type declared in other packages.
+func timports(typ gofire.Typ) []string {
+	var name, path string
+	switch t := typ.(type) {
+	case gofire.TStruct:
+		name, path = t.Typ, t.Import
+	case gofire.TNamed:
+		name, path = t.Typ, t.Import
+	case gofire.TText:
+		// Text types parsed by custom parsers could be pointers.
+		name, path = strings.TrimPrefix(t.Typ, "*"), t.Import
+	case gofire.TPtr:
+		return timports(t.ETyp)
+	case gofire.TSlice:
+		return timports(t.ETyp)
+	case gofire.TDuration, gofire.TTime:
+		return []string{`"time"`}
+	}
+	if path == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %q", strings.Split(name, ".")[0], path)}
+}
+
+// convert converts parsed parameter back to its original named type if needed.
+func convert(p Parameter) string {
+	switch p.Named.(type) {
+	case nil:
+		return p.Name
+	case gofire.TPtr:
+		return fmt.Sprintf("(%s)(%s)", p.Named.Type(), p.Name)
+	default:
+		return fmt.Sprintf("%s(%s)", p.Named.Type(), p.Name)
+	}
+}
+
+// receiver returns receiver base type name for methods.
+func (p proxy) receiver() string {
+	if p.command.Receiver == nil {
+		return ""
+	}
+	return strings.TrimPrefix(p.command.Receiver.Type.Type(), "*")
+}
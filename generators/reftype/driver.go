@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/1pkg/gofire"
+	"github.com/1pkg/gofire/config"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -19,15 +21,34 @@ type driver struct {
 	internal.Driver
 	bytes.Buffer
 	usageList []string
+	argList   []string
+	argDocs   []string
 	printList []string
+	repeats   map[string]bool
+	envs      map[string]string
+	defaults  map[string]string
+	types     map[string]gofire.Typ
+	required  []string
+	xor       map[string][]string
+	with      map[string][]string
+	implies   map[string]string
 }
 
 func (d driver) Output(cmd gofire.Command) (string, error) {
 	var buf bytes.Buffer
+	if cmd.Config {
+		d.usageList = append(d.usageList, fmt.Sprintf("--%s=%q", config.Flag, ""), fmt.Sprintf("--%s=false", config.PrintFlag))
+		d.printList = append(
+			d.printList,
+			fmt.Sprintf("--%s string %s (default %q)", config.Flag, internal.ConfigDoc, ""),
+			fmt.Sprintf("--%s bool %s (default false)", config.PrintFlag, internal.PrintConfigDoc),
+		)
+	}
 	sort.Strings(d.usageList)
 	sort.Strings(d.printList)
-	u := strings.Join(d.usageList, " ")
-	p := strings.Join(d.printList, " ")
+	// Arguments are kept in their positional order after the flags.
+	u := strings.Join(append(d.usageList, d.argList...), " ")
+	p := strings.Join(append(d.printList, d.argDocs...), " ")
 	if _, err := fmt.Fprintf(
 		&buf,
 		`
@@ -44,20 +65,22 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 				}
 			}
 		`,
-		cmd.Doc,
-		fmt.Sprintf("%s %s [--help]", cmd.Function, u),
+		internal.Manual(cmd),
+		fmt.Sprintf("%s %s [--help]", cmd.Title(), u),
 		fmt.Sprintf("%s, %s", cmd.Definition, p),
 	); err != nil {
 		return "", err
 	}
-	if _, err := buf.WriteString(
+	if _, err := fmt.Fprintf(
+		&buf,
 		`
 			defer func() {
-				if err != nil {
+				if %s {
 					help()
 				}
 			}()
 		`,
+		internal.Failed(cmd),
 	); err != nil {
 		return "", err
 	}
@@ -82,6 +105,89 @@ func (d driver) Output(cmd gofire.Command) (string, error) {
 	); err != nil {
 		return "", err
 	}
+	// Environment variables values are tokenized the same way as the flags values.
+	if _, err := buf.WriteString(
+		internal.Envs(
+			d.envs,
+			"func(name string) bool { _, ok := flags[name]; return ok }",
+			"func(name, v string) error { flags[name] = v; return nil }",
+		),
+	); err != nil {
+		return "", err
+	}
+	// Configuration values are tokenized the same way as the flags values, but they are validated
+	// right away so the errors name the configuration key; the flags raw defaults are used
+	// to print the flags that weren't provided.
+	if cmd.Config {
+		names := make([]string, 0, len(d.defaults))
+		for name := range d.defaults {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var defaults, types strings.Builder
+		for _, name := range names {
+			_, _ = fmt.Fprintf(&defaults, "%q: %q,", name, d.defaults[name])
+			if typ, ok := d.types[name]; ok {
+				_, _ = fmt.Fprintf(&types, "%q: %#v,", name, typ)
+			}
+		}
+		if _, err := fmt.Fprintf(
+			&buf,
+			`
+				{
+					_config, _printConfig := flags[%q], flags[%q] == "true"
+					defaults := map[string]string{%s}
+					types := map[string]gofire.Typ{%s}
+					set := func(name, v string) (err error) {
+						if typ, ok := types[name]; ok {
+							// lists and maps are configured as comma separated values, so they are converted to literals first.
+							if v, err = reftype.Literal(typ, v); err != nil {
+								return err
+							}
+							if _, _, err := parsers.ParseTypeValue(typ, v); err != nil {
+								return err
+							}
+						}
+						flags[name] = v
+						return nil
+					}
+					%s
+				}
+			`,
+			config.Flag,
+			config.PrintFlag,
+			defaults.String(),
+			types.String(),
+			internal.Config(
+				"func(name string) bool { _, ok := defaults[name]; return ok }",
+				"func(name string) bool { _, ok := flags[name]; return ok }",
+				"set",
+				`
+					for name, v := range defaults {
+						if f, ok := flags[name]; ok {
+							v = f
+						}
+						values[name] = v
+					}
+				`,
+			),
+		); err != nil {
+			return "", err
+		}
+	}
+	changed := "func(name string) bool { _, ok := flags[name]; return ok }"
+	if _, err := buf.WriteString(internal.Required(d.required, changed)); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Exclusive(d.xor, changed)); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Together(d.with, changed)); err != nil {
+		return "", err
+	}
+	if _, err := buf.WriteString(internal.Implies(d.implies, changed)); err != nil {
+		return "", err
+	}
 	if _, err := buf.ReadFrom(&d.Buffer); err != nil {
 		return "", err
 	}
@@ -92,7 +198,17 @@ func (d *driver) Reset() error {
 	_ = d.Driver.Reset()
 	d.Buffer.Reset()
 	d.usageList = nil
+	d.argList = nil
+	d.argDocs = nil
 	d.printList = nil
+	d.repeats = make(map[string]bool)
+	d.envs = make(map[string]string)
+	d.defaults = make(map[string]string)
+	d.types = make(map[string]gofire.Typ)
+	d.required = nil
+	d.xor = make(map[string][]string)
+	d.with = make(map[string][]string)
+	d.implies = make(map[string]string)
 	return nil
 }
 
@@ -106,12 +222,14 @@ func (d driver) Imports() []string {
 		`"fmt"`,
 		`"strconv"`,
 		`"strings"`,
+		`"time"`,
 		`"unicode"`,
 		`"os"`,
 		`"github.com/1pkg/gofire"`,
 		`"github.com/1pkg/gofire/parsers"`,
 		`"github.com/1pkg/gofire/generators/reftype"`,
 		`"github.com/mitchellh/mapstructure"`,
+		`gofireconfig "github.com/1pkg/gofire/config"`,
 	}
 }
 
@@ -126,6 +244,8 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 			a.Type.Type(),
 		)
 	}
+	offset := d.Len()
+	defer internal.Fallback(&d.Buffer, offset, fmt.Sprintf("len(args) > %d", a.Index), p.Name, p.Type, a)
 	switch p.Type.Kind() {
 	case gofire.Bool:
 	case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
@@ -133,6 +253,10 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 	case gofire.Float32, gofire.Float64:
 	case gofire.Complex64, gofire.Complex128:
 	case gofire.String:
+	case gofire.Duration, gofire.Time:
+	case gofire.Text:
+		// Text arguments are parsed directly through the type interface.
+		return d.text(p, a)
 	case gofire.Slice:
 	case gofire.Map:
 	default:
@@ -146,33 +270,33 @@ func (d *driver) VisitArgument(a gofire.Argument) error {
 	if _, err := fmt.Fprintf(d,
 		`
 			{
-				i := %d
+				i, arg := %d, %q
 				if len(args) <= i {
-					return fmt.Errorf("argument %%d-th is required", i)
+					return fmt.Errorf("argument %%s is required", arg)
 				}
 				v, _, err := parsers.ParseTypeValue(%#v, args[i])
 				if err != nil {
-					return fmt.Errorf("argument %s value %%v can't be parsed %%v", args[i], err)
+					return fmt.Errorf("argument %%s value %%v can't be parsed %%v", arg, args[i], err)
 				}
 				if err := mapstructure.Decode(v, &%s); err != nil {
-					return fmt.Errorf("argument %s value %%v can't be decoded %%v", v, err)
+					return fmt.Errorf("argument %%s value %%v can't be decoded %%v", arg, v, err)
 				}
 			}
 		`,
 		a.Index,
+		internal.Placeholder(a),
 		p.Type,
 		p.Name,
-		p.Name,
-		p.Name,
 	); err != nil {
 		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
 	}
-	d.usageList = append(d.usageList, fmt.Sprintf("arg%d", a.Index))
-	d.printList = append(d.printList, fmt.Sprintf("arg %d %s", a.Index, p.Type.Type()))
+	d.argList = append(d.argList, internal.ArgumentUsage(a, p.Type))
+	d.argDocs = append(d.argDocs, internal.ArgumentDoc(a, p.Type))
 	return nil
 }
 
 func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
+	f.Implies = internal.Implied(f, g)
 	_ = d.Driver.VisitFlag(f, g)
 	p := d.Last()
 	typ := p.Type
@@ -186,6 +310,35 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	if p.Ref != nil {
 		full = fmt.Sprintf("%s.%s", p.Ref.Group(), full)
 	}
+	if p.Required {
+		if p.Repeatable {
+			return fmt.Errorf("driver %s: required flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		d.required = append(d.required, full)
+	}
+	if len(f.Enum) > 0 && p.Repeatable {
+		return fmt.Errorf("driver %s: enum flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+	}
+	if internal.Constrained(f) && p.Repeatable {
+		return fmt.Errorf("driver %s: constrained flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+	}
+	if f.Optional && p.Repeatable {
+		return fmt.Errorf("driver %s: optional flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+	}
+	if f.Xor != "" || f.With != "" || f.Implies != "" {
+		if p.Repeatable {
+			return fmt.Errorf("driver %s: xor, with and implies flags are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if f.Xor != "" {
+			d.xor[f.Xor] = append(d.xor[f.Xor], full)
+		}
+		if f.With != "" {
+			d.with[f.With] = append(d.with[f.With], full)
+		}
+		if f.Implies != "" {
+			d.implies[full] = f.Implies
+		}
+	}
 	switch typ.Kind() {
 	case gofire.Bool:
 	case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
@@ -193,6 +346,23 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	case gofire.Float32, gofire.Float64:
 	case gofire.Complex64, gofire.Complex128:
 	case gofire.String:
+	case gofire.Duration, gofire.Time:
+	case gofire.Text:
+		if p.Repeatable {
+			return fmt.Errorf("driver %s: text flag types are not supported for repeatable groups, got a flag %s", d.Name(), p.Name)
+		}
+		if p.Env != "" {
+			d.envs[full] = p.Env
+		}
+		d.defaults[full] = raw(typ, f.Default)
+		// Text flags are parsed directly through the type interface.
+		if err := d.xflag(p, full, typ.(gofire.TText), amp, f.Default); err != nil {
+			return err
+		}
+		if _, err := d.WriteString(internal.Optional(p.Name, fmt.Sprintf("func() bool { _, ok := flags[%q]; return ok }()", full), f)); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+		return nil
 	case gofire.Slice:
 	case gofire.Map:
 	default:
@@ -203,6 +373,17 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 			p.Name,
 		)
 	}
+	if p.Repeatable {
+		if err := d.repeatable(p, typ, amp, f.Default); err != nil {
+			return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+		}
+		return nil
+	}
+	if p.Env != "" {
+		d.envs[full] = p.Env
+	}
+	d.defaults[full] = raw(typ, f.Default)
+	d.types[full] = typ
 	if _, err := fmt.Fprintf(d,
 		`
 			{
@@ -234,10 +415,152 @@ func (d *driver) VisitFlag(f gofire.Flag, g *gofire.Group) error {
 	); err != nil {
 		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
 	}
-	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, typ.Format(f.Default)))
+	v := p.Name
+	if ptr {
+		v = "*" + v
+	}
+	changed := fmt.Sprintf("func() bool { _, ok := flags[%q]; return ok }()", full)
+	if _, err := d.WriteString(internal.Enum(full, v, changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.WriteString(internal.Constraints(full, v, changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	if _, err := d.WriteString(internal.Optional(p.Name, changed, f)); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, internal.Usage(typ, f.Default)))
 	d.printList = append(
 		d.printList,
-		fmt.Sprintf("--%s %s %s (default %s)", full, typ.Type(), p.Doc, typ.Format(f.Default)),
+		fmt.Sprintf("--%s %s %s (default %s)", full, typ.Type(), p.Doc, internal.Usage(typ, f.Default)),
 	)
 	return nil
 }
+
+// text parses the text type argument through the type interface.
+func (d *driver) text(p *generators.Parameter, a gofire.Argument) error {
+	typ := p.Type.(gofire.TText)
+	if _, err := fmt.Fprintf(d,
+		`
+			{
+				i, arg := %d, %q
+				if len(args) <= i {
+					return fmt.Errorf("argument %%s is required", arg)
+				}
+				var v %s
+				if err := %s; err != nil {
+					return fmt.Errorf("argument %%s value %%v can't be parsed %%v", arg, args[i], err)
+				}
+				%s = v
+			}
+		`,
+		a.Index,
+		internal.Placeholder(a),
+		typ.Type(),
+		internal.Unmarshal(typ, "v", "args[i]"),
+		p.Name,
+	); err != nil {
+		return fmt.Errorf("driver %s: argument %w", d.Name(), err)
+	}
+	d.argList = append(d.argList, internal.ArgumentUsage(a, typ))
+	d.argDocs = append(d.argDocs, internal.ArgumentDoc(a, typ))
+	return nil
+}
+
+// xflag parses the text type flag through the type interface, the raw default text is parsed
+// the same way if the flag is not provided.
+func (d *driver) xflag(p *generators.Parameter, full string, typ gofire.TText, amp string, val interface{}) error {
+	if _, err := fmt.Fprintf(d,
+		`
+			{
+				f, ok := flags[%q]
+				if !ok || f == "" {
+					f = %s
+				}
+				var v %s
+				if f != "" {
+					if err := %s; err != nil {
+						return fmt.Errorf("flag %s value %%v can't be parsed %%v", f, err)
+					}
+				}
+				%s = %sv
+			}
+		`,
+		full,
+		typ.Format(val),
+		typ.Type(),
+		internal.Unmarshal(typ, "v", "f"),
+		p.Name,
+		p.Name,
+		amp,
+	); err != nil {
+		return fmt.Errorf("driver %s: flag %w", d.Name(), err)
+	}
+	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, internal.Usage(typ, val)))
+	d.printList = append(
+		d.printList,
+		fmt.Sprintf("--%s %s %s (default %s)", full, typ.Type(), p.Doc, internal.Usage(typ, val)),
+	)
+	return nil
+}
+
+// repeatable decodes the flag for every repeatable group index provided in the flags,
+// flags are named after the group, the index and the field e.g. --servers.0.host.
+func (d *driver) repeatable(p *generators.Parameter, typ gofire.Typ, amp string, val interface{}) error {
+	group := p.Ref.Group()
+	// Count the group indexes only once for all the group flags.
+	if !d.repeats[group] {
+		if _, err := d.WriteString(internal.Repeats(group, "arg := range flags")); err != nil {
+			return err
+		}
+		d.repeats[group] = true
+	}
+	if _, err := fmt.Fprintf(d,
+		`
+			%[1]s = make([]%[2]s, g%[3]s_n)
+			for i := range %[1]s {
+				full := fmt.Sprintf("%[3]s.%%d.%[4]s", i)
+				f, ok := flags[full]
+				v, set, err := parsers.ParseTypeValue(%#[5]v, f)
+				if err != nil {
+					return fmt.Errorf("flag %%s value %%v can't be parsed %%v", full, f, err)
+				}
+				if !ok || !set {
+					t := %[6]s
+					v = %[7]st
+				}
+				var t %[8]s
+				if err := mapstructure.Decode(v, &t); err != nil {
+					return fmt.Errorf("flag %%s value %%v can't be decoded %%v", full, v, err)
+				}
+				%[1]s[i] = %[7]st
+			}
+		`,
+		p.Name,
+		p.Type.Type(),
+		group,
+		p.Full,
+		typ,
+		typ.Format(val),
+		amp,
+		typ.Type(),
+	); err != nil {
+		return err
+	}
+	full := fmt.Sprintf("%s.N.%s", group, p.Full)
+	d.usageList = append(d.usageList, fmt.Sprintf("--%s=%s", full, internal.Usage(typ, val)))
+	d.printList = append(
+		d.printList,
+		fmt.Sprintf("--%s %s %s (default %s)", full, typ.Type(), p.Doc, internal.Usage(typ, val)),
+	)
+	return nil
+}
+
+// raw returns the flag default value in the same raw form the flag value is provided in the command line.
+func raw(typ gofire.Typ, val interface{}) string {
+	u := internal.Usage(typ, val)
+	if s, err := strconv.Unquote(u); err == nil {
+		return s
+	}
+	return u
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/1pkg/gofire/cmd"
 	"github.com/1pkg/gofire/generators"
 	"github.com/1pkg/gofire/generators/internal"
 )
@@ -38,7 +39,7 @@ func TestRefTypeDriver(t *testing.T) {
 			pckg:     "main",
 			function: "echo",
 			params:   []string{"--a=test", "--b", "100", "--c", "10", "--d=true", "--e", "10.125", "test1", "101", "11", "false", "10.5"},
-			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:10.500\n",
+			out:      "a:test b:100 c:10 d:true e:10.125 a1:test1 b1:101 c1:11 d1:false e1:10.500\n0\n",
 		},
 		"echo primitive params types should produce expected output on help flag": {
 			dir:      "echo_primitive_params",
@@ -47,8 +48,8 @@ func TestRefTypeDriver(t *testing.T) {
 			params:   []string{"--help"},
 			err:      errors.New("exit status 1"),
 			out: `echo documentation string.
-echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4 [--help]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
+echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1 [--help]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
 help requested
 exit status 2
 `,
@@ -60,8 +61,8 @@ exit status 2
 			params:   []string{"--e", "test"},
 			err:      errors.New("exit status 1"),
 			out: `echo documentation string.
-echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4 [--help]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
+echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1 [--help]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
 flag e value test can't be parsed strconv.ParseFloat: parsing "test": invalid syntax
 exit status 2
 `,
@@ -73,9 +74,9 @@ exit status 2
 			params:   []string{"--a=test", "--b", "100", "--c", "10", "--d=true", "--e", "10.125", "test1"},
 			err:      errors.New("exit status 1"),
 			out: `echo documentation string.
-echo --a="" --b=0 --c=0 --d=false --e=0.000000 arg0 arg1 arg2 arg3 arg4 [--help]
-func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) arg 0 string arg 1 int arg 2 uint64 arg 3 bool arg 4 float32
-argument 1-th is required
+echo --a="" --b=0 --c=0 --d=false --e=0.000000 A1 B1 C1 D1 E1 [--help]
+func echo(_ context.Context, a *string, b *int, c *uint64, d *bool, e *float32, a1 string, b1 int, c1 uint64, d1 bool, e1 float32) int, --a string (default "") --b int (default 0) --c uint64 (default 0) --d bool (default false) --e float32 (default 0.000000) A1 string B1 int C1 uint64 D1 bool E1 float32
+argument B1 is required
 exit status 2
 `,
 		},
@@ -84,7 +85,14 @@ exit status 2
 			pckg:     "main",
 			function: "echo",
 			params:   []string{`--a="{1,2,3,4,5}"`, `--b="{{1},{2},{3}}"`, `"{test1:{'aaa', 'bbb'}, test2:{bbb, aaa}}"`},
-			out:      "[1 2 3 4 5] [[1] [2] [3]] map[test1:['aaa' 'bbb'] test2:[bbb aaa]]\n",
+			out:      "[1 2 3 4 5] [[1] [2] [3]] map[test1:['aaa' 'bbb'] test2:[bbb aaa]]\n0\n",
+		},
+		"echo named params types should produce expected output on valid params": {
+			dir:      "echo_named_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{`--ports="{80,443}"`, `"{test:{aaa, bbb}}"`},
+			out:      "[80 443] 2 map[test:[aaa bbb]]\n",
 		},
 		"echo group params types should produce expected output on valid params": {
 			dir:      "echo_group_params",
@@ -100,6 +108,74 @@ exit status 2
 			params:   []string{`--g.b="{1:2}"`},
 			out:      "{map[key:value] map[1:2]}\n",
 		},
+		"echo repeatable group params should produce expected output on valid params": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.0.host=a", "--servers.0.port=1", "--servers.1.host=b"},
+			out:      "a:1\nb:80\n",
+		},
+		"echo repeatable group params should produce expected error on out of range index": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.999999999999.host=a"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --servers.N.host="localhost" --servers.N.port=80 [--help]
+func echo(servers []server), --servers.N.host string server host. (default "localhost") --servers.N.port int server port. (default 80)
+flag servers.999999999999.host index 999999999999 is out of range
+exit status 2
+`,
+		},
+		"echo repeatable group params should produce expected error on indexes gap": {
+			dir:      "echo_repeatable_group",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--servers.0.host=a", "--servers.2.host=b"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --servers.N.host="localhost" --servers.N.port=80 [--help]
+func echo(servers []server), --servers.N.host string server host. (default "localhost") --servers.N.port int server port. (default 80)
+group servers indexes have to be provided continuously from 0 to 2
+exit status 2
+`,
+		},
+		"echo time params should produce expected output on valid params": {
+			dir:      "echo_time_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--timeout=1.5s", "--opt.Until=2022-03-04", "2021-02-03T04:05:06Z"},
+			out:      "1.5s 2021-02-03T04:05:06Z 30s 2022-03-04\n",
+		},
+		"echo text params should produce expected output on valid params": {
+			dir:      "echo_text_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--lvl=debug", "10.0.0.1"},
+			out:      "debug 10.0.0.1 127.0.0.1\n",
+		},
+		"echo parser params should produce expected output on valid params": {
+			dir:      "echo_parser_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--opt.Region=us-2"},
+			out:      "us 2\n",
+		},
+		"echo parser params should produce expected output on default params": {
+			dir:      "echo_parser_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{},
+			out:      "eu 1\n",
+		},
+		"echo time params should produce expected output on default params": {
+			dir:      "echo_time_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--timeout=1m", "2021-02-03T04:05:06Z"},
+			out:      "1m0s 2021-02-03T04:05:06Z 30s 2021-01-02\n",
+		},
 		"echo group params types should produce expected error on invalid params": {
 			dir:      "echo_group_params",
 			pckg:     "main",
@@ -119,11 +195,637 @@ exit status 2
 			params:   []string{"1", "10", "100"},
 			err:      errors.New(`driver reftype: ellipsis argument types are not supported, got an argument a0 int`),
 		},
+		"echo required params should produce expected output on valid params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--conn.Host=localhost", "--conn.User=admin"},
+			out:      "localhost admin 5432\n",
+		},
+		"echo required params should produce expected error on missing params": {
+			dir:      "echo_required_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--conn.Port=6000"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --conn.Host="" --conn.Port=5432 --conn.User="" [--help]
+func echo(conn db), --conn.Host string database host. (required) (default "") --conn.Port int database port. (default 5432) --conn.User string database user. (required) (default "")
+required flag(s) "conn.Host", "conn.User" not set
+exit status 2
+`,
+		},
+		"echo enum params should produce expected output on valid params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Format=json", "--log.Verbosity=2", "--log.Level=warn", "debug"},
+			out:      "json 2 2 0\n",
+		},
+		"echo enum params should produce expected output on constants literal values": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Level=2", "0"},
+			out:      "text 0 2 0\n",
+		},
+		"echo enum params should produce expected output on default params": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"info"},
+			out:      "text 0 1 1\n",
+		},
+		"echo enum params should produce expected error on invalid enum flags": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Format=xml", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL [--help]
+func echo(log logopts, lvl level), --log.Format string log format. (one of text, json) (default "text") --log.Level level log level. (one of debug, info, warn) (default "info") --log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag log.Format value xml is not one of text, json
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on provided default value outside of enum": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Verbosity=0", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL [--help]
+func echo(log logopts, lvl level), --log.Format string log format. (one of text, json) (default "text") --log.Level level log level. (one of debug, info, warn) (default "info") --log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag log.Verbosity value 0 is not one of 1, 2, 3
+exit status 2
+`,
+		},
+		"echo enum params should produce expected error on invalid constant names": {
+			dir:      "echo_enum_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--log.Level=error", "info"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --log.Format="text" --log.Level="info" --log.Verbosity=0 LVL [--help]
+func echo(log logopts, lvl level), --log.Format string log format. (one of text, json) (default "text") --log.Level level log level. (one of debug, info, warn) (default "info") --log.Verbosity int log verbosity. (one of 1, 2, 3) (default 0) LVL level
+flag logLevel value error can't be parsed error is not one of debug, info, warn
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected output on valid params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Host=api.local", "--srv.Port=443", "--srv.Name=web", "--srv.Tags={a,b}"},
+			out:      "api.local 443 web [a b]\n",
+		},
+		"echo constrained params should produce expected output on default params": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "localhost 8080  []\n",
+		},
+		"echo constrained params should produce expected error on out of range flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Port=70000"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{} [--help]
+func echo(srv server), --srv.Host string server host. (default "localhost") --srv.Name string server name. (default "") --srv.Port int server port. (default 8080) --srv.Tags []string server tags. (default []string{})
+flag srv.Port value 70000 is greater than max 65535
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on not matching pattern flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Host=API"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{} [--help]
+func echo(srv server), --srv.Host string server host. (default "localhost") --srv.Name string server name. (default "") --srv.Port int server port. (default 8080) --srv.Tags []string server tags. (default []string{})
+flag srv.Host value "API" doesn't match pattern ^[a-z.]+$
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on provided default value outside of constraints": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Name="},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{} [--help]
+func echo(srv server), --srv.Host string server host. (default "localhost") --srv.Name string server name. (default "") --srv.Port int server port. (default 8080) --srv.Tags []string server tags. (default []string{})
+flag srv.Name value length 0 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on too short flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Name=w"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{} [--help]
+func echo(srv server), --srv.Host string server host. (default "localhost") --srv.Name string server name. (default "") --srv.Port int server port. (default 8080) --srv.Tags []string server tags. (default []string{})
+flag srv.Name value length 1 is less than minlen 2
+exit status 2
+`,
+		},
+		"echo implied params should produce expected output on valid params": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=cert.pem", "--sec.Key=key.pem"},
+			out:      "cert.pem key.pem\n",
+		},
+		"echo implied params should produce expected output on implied flag only": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Key=key.pem"},
+			out:      " key.pem\n",
+		},
+		"echo implied params should produce expected error on missing implied flag": {
+			dir:      "echo_implied_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=cert.pem"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --sec.Cert="" --sec.Key="" [--help]
+func echo(sec tls), --sec.Cert string tls certificate path. (implies sec.Key) (default "") --sec.Key string tls key path. (default "")
+flag sec.Cert requires flag sec.Key to be provided
+exit status 2
+`,
+		},
+		"echo constrained params should produce expected error on too long list flags": {
+			dir:      "echo_constrained_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--srv.Tags={a,b,c}"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --srv.Host="localhost" --srv.Name="" --srv.Port=8080 --srv.Tags=[]string{} [--help]
+func echo(srv server), --srv.Host string server host. (default "localhost") --srv.Name string server name. (default "") --srv.Port int server port. (default 8080) --srv.Tags []string server tags. (default []string{})
+flag srv.Tags value length 3 is greater than maxlen 2
+exit status 2
+`,
+		},
+		"echo related params should produce expected output on valid params": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--src.File=in.txt", "--sec.Cert=c.pem", "--sec.Key=k.pem"},
+			out:      "in.txt  c.pem k.pem\n",
+		},
+		"echo related params should produce expected error on mutually exclusive flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--src.File=in.txt", "--src.URL=http://in"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --sec.Cert="" --sec.Key="" --src.File="" --src.URL="" [--help]
+func echo(src source, sec tls), --sec.Cert string tls certificate path. (with tls) (default "") --sec.Key string tls key path. (with tls) (default "") --src.File string input file path. (xor input) (default "") --src.URL string input url. (xor input) (default "")
+flag(s) "src.File", "src.URL" of group input are mutually exclusive
+exit status 2
+`,
+		},
+		"echo related params should produce expected error on not together flags": {
+			dir:      "echo_related_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--sec.Cert=c.pem"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --sec.Cert="" --sec.Key="" --src.File="" --src.URL="" [--help]
+func echo(src source, sec tls), --sec.Cert string tls certificate path. (with tls) (default "") --sec.Key string tls key path. (with tls) (default "") --src.File string input file path. (xor input) (default "") --src.URL string input url. (xor input) (default "")
+flag(s) "sec.Key" of group tls not set, required together with "sec.Cert"
+exit status 2
+`,
+		},
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false 30 nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--lim.Retries=3"},
+			out:      "false 30 3\n",
+		},
+		"echo directive params should produce expected output on default params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "6\n",
+		},
+		"echo directive params should produce expected output on valid params": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--mult=3", "1", "2"},
+			out:      "9\n",
+		},
+		"echo directive params should produce expected error on missing argument": {
+			dir:      "echo_directive_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--mult=3", "1"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --mult=2 LEFT RIGHT [--help]
+func echo(a int, b int, mult *int), --mult int result multiplier. (default 2) LEFT int left operand. RIGHT int
+argument RIGHT is required
+exit status 2
+`,
+		},
+		"echo named command should produce expected output on valid params": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"hello"},
+			out:      "hello\n",
+		},
+		"echo named command should produce expected error on missing argument": {
+			dir:      "echo_named_command",
+			pckg:     "main",
+			function: "echo",
+			err:      errors.New("exit status 1"),
+			out: `echo prints the provided text.
+aliases: et
+examples:
+	echo-text hello
+echo-text TEXT [--help]
+func echo(text string), TEXT string
+argument TEXT is required
+exit status 2
+`,
+		},
+		"echo documented args should produce expected output on valid params": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1", "2"},
+			out:      "3\n",
+		},
+		"echo documented args should produce expected error on missing argument": {
+			dir:      "echo_documented_args",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"1"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo LEFT RIGHT [--help]
+func echo(left int, right int /* right operand. */), LEFT int left operand. RIGHT int right operand.
+argument RIGHT is required
+exit status 2
+`,
+		},
+		"echo optional args should produce expected output on omitted optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt"},
+			out:      "log.txt 10\n",
+		},
+		"echo optional args should produce expected output on provided optional argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			params:   []string{"log.txt", "5"},
+			out:      "log.txt 5\n",
+		},
+		"echo optional args should produce expected error on missing argument": {
+			dir:      "echo_optional_args",
+			pckg:     "main",
+			function: "tail",
+			err:      errors.New("exit status 1"),
+			out: `tail prints the file lines.
+tail FILE [LINES=10] [--help]
+func tail(file string, lines int), FILE string LINES int number of lines. (default 10)
+argument FILE is required
+exit status 2
+`,
+		},
+		"echo output results should produce expected text output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"10"},
+			out:      "NAME   AGE\nalice  30\n",
+		},
+		"echo output results should produce expected json output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=json", "0"},
+			out: `[
+  {
+    "name": "alice",
+    "age": 30
+  },
+  {
+    "name": "bob",
+    "age": 7
+  }
+]
+`,
+		},
+		"echo output results should produce expected yaml output on valid params": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"-output", "yaml", "0"},
+			out: `- name: alice
+  age: 30
+- name: bob
+  age: 7
+`,
+		},
+		"echo output results should produce expected error on error result": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"200"},
+			err:      errors.New("exit status 1"),
+			out: `age is out of range
+exit status 2
+`,
+		},
+		"echo output results should produce expected error on unsupported output format": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "users",
+			params:   []string{"--output=xml", "0"},
+			err:      errors.New("exit status 1"),
+			out: `output format "xml" is not supported, one of text, json, yaml is expected
+exit status 2
+`,
+		},
+		"echo output results should not print silent command results": {
+			dir:      "echo_output_results",
+			pckg:     "main",
+			function: "greet",
+			params:   []string{"bob"},
+			out:      "hello bob\n",
+		},
 	}
 	for tname, tcase := range table {
 		t.Run(tname, func(t *testing.T) {
 			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
-			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameRefType, filepath.Join("tcases", tcase.dir), tcase.pckg, tcase.function)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameRefType, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function})
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestRefTypeDriverEnv(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		env      map[string]string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo env params should produce expected output on env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_VERBOSE": "true", "APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			out:      "true remote 6000\n",
+		},
+		"echo env params should prefer explicit flags over env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"APP_CONN_HOST": "remote", "DB_PORT": "6000"},
+			params:   []string{"--conn.Host=local"},
+			out:      "false local 6000\n",
+		},
+		"echo env params should produce expected output on default params": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "false localhost 5432\n",
+		},
+		"echo env params should produce expected error on invalid env variables": {
+			dir:      "echo_env_params",
+			pckg:     "main",
+			function: "echo",
+			env:      map[string]string{"DB_PORT": "port"},
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --conn.Host="localhost" --conn.Port=5432 --verbose=false [--help]
+func echo(verbose *bool, conn db), --conn.Host string database host. (env APP_CONN_HOST) (default "localhost") --conn.Port int database port. (env DB_PORT) (default 5432) --verbose bool (env APP_VERBOSE) (default false)
+flag connPort value port can't be parsed strconv.ParseInt: parsing "port": invalid syntax
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			for k, v := range tcase.env {
+				t.Setenv(k, v)
+			}
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameRefType, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithEnvPrefix("APP"))
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestRefTypeDriverOptional(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo optional params should produce expected output on default params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			out:      "nil nil nil\n",
+		},
+		"echo optional params should produce expected output on valid params": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--verbose=true", "--lim.Retries=3"},
+			out:      "true nil 3\n",
+		},
+		"echo optional params should produce expected output on params set to defaults": {
+			dir:      "echo_optional_params",
+			pckg:     "main",
+			function: "echo",
+			params:   []string{"--verbose=false", "--lim.Timeout=30"},
+			out:      "false 30 nil\n",
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			exec := internal.GoExec("run -tags=tcases .", tcase.params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameRefType, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithOptional())
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
+			}
+			if tcase.out != out {
+				t.Fatalf("expected output %q but got %q", tcase.out, out)
+			}
+		})
+	}
+}
+
+func TestRefTypeDriverConfig(t *testing.T) {
+	table := map[string]struct {
+		dir      string
+		pckg     string
+		function string
+		config   string
+		data     string
+		params   []string
+		out      string
+		err      error
+	}{
+		"echo config params should produce expected output on json config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"verbose": true, "db": {"Host": "remote", "Port": 6000}}`,
+			out:      "true [] map[] remote 6000\n",
+		},
+		"echo config params should produce expected output on yaml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Host: remote\n",
+			out:      "false [] map[] remote 5432\n",
+		},
+		"echo config params should produce expected output on toml config": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "verbose = true\n[db]\nPort = 6000\n",
+			out:      "true [] map[] localhost 6000\n",
+		},
+		"echo config params should produce expected output on yaml config with lists and maps": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "tags: [x, 'y z']\nlabels: {a: 1, b: 2}\n",
+			out:      "false [x y z] map[a:1 b:2] localhost 5432\n",
+		},
+		"echo config params should produce expected error on config list items with commas": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "tags: ['x,y', z]\n",
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --config="" --db.Host="localhost" --db.Port=5432 --labels=map[string]int{} --print-config=false --tags=[]string{} --verbose=false [--help]
+func echo(verbose *bool, tags *[]string, labels *map[string]int, db conn), --config string path to the configuration file (json, yaml or toml). (default "") --db.Host string database host. (default "localhost") --db.Port int database port. (default 5432) --labels map[string]int (default map[string]int{}) --print-config bool print the effective configuration and exit. (default false) --tags []string (default []string{}) --verbose bool (default false)
+config key tags parse error: item "x,y" can't be represented as a literal item
+exit status 2
+`,
+		},
+		"echo config params should prefer explicit flags over config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Host": "remote", "Port": 6000}}`,
+			params:   []string{"--db.Host=local"},
+			out:      "false [] map[] local 6000\n",
+		},
+		"echo config params should produce expected output on print config flag": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.yaml",
+			data:     "db:\n  Port: 6000\n",
+			params:   []string{"--verbose=true", "--print-config=true"},
+			out: `{
+  "db": {
+    "Host": "localhost",
+    "Port": "6000"
+  },
+  "labels": "map[string]int{}",
+  "tags": "[]string{}",
+  "verbose": "true"
+}
+`,
+		},
+		"echo config params should produce expected error on unknown config keys": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.json",
+			data:     `{"db": {"Name": "test"}}`,
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --config="" --db.Host="localhost" --db.Port=5432 --labels=map[string]int{} --print-config=false --tags=[]string{} --verbose=false [--help]
+func echo(verbose *bool, tags *[]string, labels *map[string]int, db conn), --config string path to the configuration file (json, yaml or toml). (default "") --db.Host string database host. (default "localhost") --db.Port int database port. (default 5432) --labels map[string]int (default map[string]int{}) --print-config bool print the effective configuration and exit. (default false) --tags []string (default []string{}) --verbose bool (default false)
+config key db.Name is unknown
+exit status 2
+`,
+		},
+		"echo config params should produce expected error on invalid config values": {
+			dir:      "echo_config_params",
+			pckg:     "main",
+			function: "echo",
+			config:   "config.toml",
+			data:     "[db]\nPort = 'port'\n",
+			err:      errors.New("exit status 1"),
+			out: `echo documentation string.
+echo --config="" --db.Host="localhost" --db.Port=5432 --labels=map[string]int{} --print-config=false --tags=[]string{} --verbose=false [--help]
+func echo(verbose *bool, tags *[]string, labels *map[string]int, db conn), --config string path to the configuration file (json, yaml or toml). (default "") --db.Host string database host. (default "localhost") --db.Port int database port. (default 5432) --labels map[string]int (default map[string]int{}) --print-config bool print the effective configuration and exit. (default false) --tags []string (default []string{}) --verbose bool (default false)
+config key db.Port parse error: strconv.ParseInt: parsing "port": invalid syntax
+exit status 2
+`,
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tcase.config)
+			if err := os.WriteFile(path, []byte(tcase.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			params := append([]string{"--config=" + path}, tcase.params...)
+			exec := internal.GoExec("run -tags=tcases .", params...)
+			out, err := exec.RunOnTest(context.TODO(), generators.DriverNameRefType, filepath.Join("tcases", tcase.dir), tcase.pckg, []string{tcase.function}, cmd.WithConfig())
 			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
 				t.Fatalf("expected error message %q but got %q\n%v", tcase.err, err, out)
 			}
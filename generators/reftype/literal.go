@@ -0,0 +1,49 @@
+package reftype
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/1pkg/gofire"
+)
+
+// Literal converts comma separated configuration value of a list or a map flag, e.g. a,b or a=1,b=2,
+// into the flag literal value, e.g. {a,b} or {a:1,b:2}. Literal values are returned without their type prefix
+// and other values are returned as they are.
+// Items that contain the literal delimiters can't be represented as literal items and are reported as errors.
+func Literal(t gofire.Typ, v string) (string, error) {
+	if tp, ok := t.(gofire.TPtr); ok {
+		t = tp.ETyp
+	}
+	switch t.Kind() {
+	case gofire.Slice, gofire.Array, gofire.Map:
+	default:
+		return v, nil
+	}
+	if v == "" {
+		return v, nil
+	}
+	// Literal values could be prefixed with their type, e.g. []string{a,b}, as they are printed by the configuration.
+	if i := strings.Index(v, "{"); i >= 0 && strings.HasSuffix(v, "}") && !strings.Contains(v[:i], ",") {
+		return v[i:], nil
+	}
+	items, err := csv.NewReader(strings.NewReader(v)).Read()
+	if err != nil {
+		return "", fmt.Errorf("invalid value %q can't be parsed as comma separated values", v)
+	}
+	for i, item := range items {
+		if strings.ContainsAny(item, ",{}") {
+			return "", fmt.Errorf("item %q can't be represented as a literal item", item)
+		}
+		if t.Kind() != gofire.Map {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || strings.Contains(kv[0], ":") {
+			return "", fmt.Errorf("item %q can't be represented as a literal key value pair", item)
+		}
+		items[i] = kv[0] + ":" + kv[1]
+	}
+	return "{" + strings.Join(items, ",") + "}", nil
+}
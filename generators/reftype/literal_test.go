@@ -0,0 +1,65 @@
+package reftype
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/1pkg/gofire"
+)
+
+func TestLiteral(t *testing.T) {
+	table := map[string]struct {
+		typ gofire.Typ
+		v   string
+		lit string
+		err error
+	}{
+		"scalar value should be returned as is": {
+			typ: gofire.TPrimitive{TKind: gofire.String},
+			v:   "a,b",
+			lit: "a,b",
+		},
+		"list value should produce expected literal": {
+			typ: gofire.TPtr{ETyp: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+			v:   `a,"b c"`,
+			lit: "{a,b c}",
+		},
+		"map value should produce expected literal": {
+			typ: gofire.TMap{KTyp: gofire.TPrimitive{TKind: gofire.String}, VTyp: gofire.TPrimitive{TKind: gofire.Int}},
+			v:   "a=1,b=2",
+			lit: "{a:1,b:2}",
+		},
+		"literal value should be returned as is": {
+			typ: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.Int}},
+			v:   "{1,2}",
+			lit: "{1,2}",
+		},
+		"typed literal value should produce expected literal": {
+			typ: gofire.TMap{KTyp: gofire.TPrimitive{TKind: gofire.String}, VTyp: gofire.TPrimitive{TKind: gofire.Int}},
+			v:   "map[string]int{}",
+			lit: "{}",
+		},
+		"list item with comma should produce expected error": {
+			typ: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}},
+			v:   `"a,b",c`,
+			err: errors.New(`item "a,b" can't be represented as a literal item`),
+		},
+		"map item without value should produce expected error": {
+			typ: gofire.TMap{KTyp: gofire.TPrimitive{TKind: gofire.String}, VTyp: gofire.TPrimitive{TKind: gofire.Int}},
+			v:   "a",
+			err: errors.New(`item "a" can't be represented as a literal key value pair`),
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			lit, err := Literal(tcase.typ, tcase.v)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if tcase.lit != lit {
+				t.Fatalf("expected literal %q but got %q", tcase.lit, lit)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type conn struct {
+	// database host.
+	Host string `gofire:"default=localhost"`
+	// database port.
+	Port int `gofire:"default=5432"`
+}
+
+// echo documentation string.
+func echo(verbose *bool, tags *[]string, labels *map[string]int, db conn) {
+	fmt.Println(*verbose, *tags, *labels, db.Host, db.Port)
+}
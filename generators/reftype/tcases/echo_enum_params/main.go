@@ -0,0 +1,29 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type level int
+
+const (
+	debug level = iota
+	info
+	warn
+)
+
+type logopts struct {
+	// log format.
+	Format string `gofire:"enum={text,json},default=text"`
+	// log verbosity.
+	Verbosity int `gofire:"enum={1,2,3}"`
+	// log level.
+	Level level `gofire:"default=info"`
+}
+
+// echo documentation string.
+func echo(log logopts, lvl level) {
+	fmt.Println(log.Format, log.Verbosity, log.Level, lvl)
+}
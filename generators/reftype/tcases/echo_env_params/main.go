@@ -0,0 +1,19 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type db struct {
+	// database host.
+	Host string `gofire:"default=localhost"`
+	// database port.
+	Port int `gofire:"env=DB_PORT,default=5432"`
+}
+
+// echo documentation string.
+func echo(verbose *bool, conn db) {
+	fmt.Println(*verbose, conn.Host, conn.Port)
+}
@@ -0,0 +1,15 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+// echo documentation string.
+//
+//gofire:command name=echo-text aliases=et long='echo prints the provided text.'
+//gofire:command example='echo-text hello'
+func echo(text string) {
+	fmt.Println(text)
+}
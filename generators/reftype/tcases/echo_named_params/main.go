@@ -0,0 +1,15 @@
+//go:build tcases
+
+package main
+
+import "fmt"
+
+// Labels is a set of labels.
+type Labels = map[string][]string
+
+// Ports is a list of ports.
+type Ports []int
+
+func echo(ports *Ports, labels Labels) {
+	fmt.Println(*ports, len(*ports), labels)
+}
@@ -0,0 +1,29 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type limits struct {
+	// request timeout.
+	Timeout *int `gofire:"default=30"`
+	// request retries.
+	Retries *int `gofire:"optional"`
+}
+
+// echo documentation string.
+func echo(verbose *bool, lim limits) {
+	vals := []interface{}{"nil", "nil", "nil"}
+	if verbose != nil {
+		vals[0] = *verbose
+	}
+	if lim.Timeout != nil {
+		vals[1] = *lim.Timeout
+	}
+	if lim.Retries != nil {
+		vals[2] = *lim.Retries
+	}
+	fmt.Println(vals...)
+}
@@ -0,0 +1,35 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type region struct {
+	name string
+	zone int
+}
+
+func parseRegion(s string) (region, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return region{}, fmt.Errorf("invalid region %s", s)
+	}
+	var zone int
+	if _, err := fmt.Sscanf(parts[1], "%d", &zone); err != nil {
+		return region{}, err
+	}
+	return region{name: parts[0], zone: zone}, nil
+}
+
+type opts struct {
+	// deployment region.
+	Region region `gofire:"parser=parseRegion,default=eu-1"`
+}
+
+// echo documentation string.
+func echo(opt opts) {
+	fmt.Println(opt.Region.name, opt.Region.zone)
+}
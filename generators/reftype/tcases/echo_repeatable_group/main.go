@@ -0,0 +1,21 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+)
+
+type server struct {
+	// server host.
+	host string `gofire:"default=localhost"`
+	// server port.
+	port int `gofire:"default=80"`
+}
+
+// echo documentation string.
+func echo(servers []server) {
+	for _, s := range servers {
+		fmt.Printf("%s:%d\n", s.host, s.port)
+	}
+}
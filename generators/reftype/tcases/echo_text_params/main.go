@@ -0,0 +1,35 @@
+//go:build tcases
+
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// level is a log level parsed through flag.Value.
+type level string
+
+func (l level) String() string {
+	return string(l)
+}
+
+func (l *level) Set(s string) error {
+	switch s {
+	case "debug", "info":
+		*l = level(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown level %s", s)
+	}
+}
+
+type opts struct {
+	// bind address.
+	Addr net.IP `gofire:"default=127.0.0.1"`
+}
+
+// echo documentation string.
+func echo(lvl *level, host net.IP, opt opts) {
+	fmt.Println(*lvl, host, opt.Addr)
+}
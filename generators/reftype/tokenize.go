@@ -11,7 +11,7 @@ func Tokenize(tokens []string) (args []string, flags map[string]string, err erro
 	var flname = func(token string) (string, error) {
 		fln := strings.Replace(token, "--", "", 1)
 		for _, r := range fln {
-			if r != '.' && !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if r != '.' && r != '-' && !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
 				return "", fmt.Errorf("flag name %s is not alphanumeric and can't be tokenized", fln)
 			}
 		}
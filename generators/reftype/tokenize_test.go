@@ -49,6 +49,14 @@ func TestTokenize(t *testing.T) {
 				"az":  "az-bd",
 			},
 		},
+		"valid dashed flags names tokens should return expected result": {
+			tokens: []string{"--print-config=true", "--db.max-conns", "10"},
+			args:   []string{},
+			flags: map[string]string{
+				"print-config": "true",
+				"db.max-conns": "10",
+			},
+		},
 		"mixture of valid params tokens at the end should return expected result": {
 			tokens: []string{"--d.d=aaaa", "--fff", "20.20", "--az", "az-bd", "100", `"zzz"`, "true"},
 			args:   []string{"100", `"zzz"`, "true"},
@@ -0,0 +1,323 @@
+package generators
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/1pkg/gofire"
+	"golang.org/x/tools/imports"
+)
+
+// GenerateTree generates cli command tree using provided driver to provided writer output.
+// Each tree command is generated as a separate subcommand dispatched by the first positional argument.
+func GenerateTree(ctx context.Context, name DriverName, tree gofire.Tree, w io.Writer) error {
+	driver, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	root := rproxy{driver: driver, tree: tree}
+	if err := collide(root.Output(), tree.Commands...); err != nil {
+		return err
+	}
+	functions := map[string]bool{root.Function(): true}
+	titles := make(map[string]bool, len(tree.Commands))
+	srcs := make([][]byte, 0, len(tree.Commands)+1)
+	for _, cmd := range tree.Commands {
+		f := proxy{driver: driver, command: cmd}.Function()
+		if functions[f] {
+			return fmt.Errorf("command %s is duplicated in tree %s", f, tree.Name)
+		}
+		functions[f] = true
+		// Commands are dispatched by their names and aliases, so they have to be unique as well.
+		for _, title := range append([]string{cmd.Title()}, cmd.Aliases...) {
+			if titles[title] {
+				return fmt.Errorf("command %s is duplicated in tree %s", title, tree.Name)
+			}
+			titles[title] = true
+		}
+		src, err := generate(ctx, driver, cmd, false, false)
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, src)
+	}
+	src, err := execute(rtemplate, root)
+	if err != nil {
+		return err
+	}
+	if src, err = merge(append(srcs, src)...); err != nil {
+		return err
+	}
+	if src, err = imports.Process("", src, nil); err != nil {
+		return err
+	}
+	if _, err := w.Write(src); err != nil {
+		return err
+	}
+	return nil
+}
+
+// merge merges multiple generated sources of the same package into single source
+// by keeping the header of the first source and collecting all imports together.
+func merge(srcs ...[]byte) ([]byte, error) {
+	var header []byte
+	var imports []string
+	var bodies [][]byte
+	for i, src := range srcs {
+		fset := token.NewFileSet()
+		f, err := goparser.ParseFile(fset, "", src, goparser.ImportsOnly|goparser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		end := fset.Position(f.Name.End()).Offset
+		if i == 0 {
+			header = src[:end]
+		}
+		for _, spec := range f.Imports {
+			imports = append(imports, string(src[fset.Position(spec.Pos()).Offset:fset.Position(spec.End()).Offset]))
+		}
+		if l := len(f.Decls); l > 0 {
+			end = fset.Position(f.Decls[l-1].End()).Offset
+		}
+		bodies = append(bodies, src[end:])
+	}
+	var buf bytes.Buffer
+	if _, err := fmt.Fprintf(&buf, "%s\n\nimport(\n%s\n)\n", header, strings.Join(imports, "\n")); err != nil {
+		return nil, err
+	}
+	for _, body := range bodies {
+		if _, err := fmt.Fprintf(&buf, "%s\n", body); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// rproxy defines data object proxy for tree root generator.
+type rproxy struct {
+	driver Driver
+	tree   gofire.Tree
+}
+
+func (p rproxy) Package() string {
+	return p.tree.Package
+}
+
+func (p rproxy) Main() bool {
+	return p.tree.Package == "main"
+}
+
+func (p rproxy) Function() string {
+	// Tree name could be derived from a directory so it needs to be sanitized first.
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, p.tree.Name)
+	return "Command" + strings.ReplaceAll(strings.Title(name), " ", "") + strings.Title(string(p.driver.Name()))
+}
+
+func (p rproxy) Doc() string {
+	return fmt.Sprintf("// %s is autogenerated cli interface for %s commands tree.", p.Function(), p.tree.Name)
+}
+
+func (p rproxy) Import() string {
+	imports := []string{`"context"`, `"errors"`, `"fmt"`, `"io"`, `"os"`, `"strings"`}
+	if p.Main() {
+		imports = append(imports, `"os/signal"`)
+	}
+	if p.Printed() {
+		imports = append(imports, `gofireoutput "github.com/1pkg/gofire/output"`)
+	}
+	sort.Strings(imports)
+	return strings.Join(imports, "\n")
+}
+
+func (p rproxy) Help() string {
+	var buf strings.Builder
+	if p.tree.Doc != "" {
+		_, _ = fmt.Fprintln(&buf, p.tree.Doc)
+	}
+	_, _ = fmt.Fprintf(&buf, "%s command [--help -h]\n", p.tree.Name)
+	_, _ = fmt.Fprint(&buf, "commands:")
+	for _, cmd := range p.tree.Commands {
+		// Hidden commands are still dispatched, but they are not listed.
+		if cmd.Hidden {
+			continue
+		}
+		// Note that tabs are used for alignment here as spaces are collapsed by generator.
+		_, _ = fmt.Fprintf(&buf, "\n\t%s", cmd.Title())
+		if len(cmd.Aliases) > 0 {
+			_, _ = fmt.Fprintf(&buf, " (%s)", strings.Join(cmd.Aliases, ", "))
+		}
+		if digest := strings.Split(cmd.Doc, "\n")[0]; digest != "" {
+			_, _ = fmt.Fprintf(&buf, "\t%s", digest)
+		}
+	}
+	return fmt.Sprintf("%q", buf.String())
+}
+
+func (p rproxy) Output() bool {
+	// results are printed only by the main entrypoint, so the output format is only needed there.
+	if !p.Main() {
+		return false
+	}
+	for _, cmd := range p.tree.Commands {
+		if printable(cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p rproxy) Printed() bool {
+	// printed output is only handled by the main entrypoint as well.
+	if !p.Main() {
+		return false
+	}
+	for _, cmd := range p.tree.Commands {
+		if Printed(cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+// Shared returns the persistent group flags shared by the tree commands
+// along with whether they take a separate value, formatted as map literal entries.
+func (p rproxy) Shared() string {
+	shared := make(map[string]bool)
+	for _, cmd := range p.tree.Commands {
+		if cmd.Receiver == nil || !cmd.Receiver.Persistent {
+			continue
+		}
+		for _, f := range cmd.Receiver.Flags {
+			typ := f.Type
+			if t, ok := typ.(gofire.TPtr); ok {
+				typ = t.ETyp
+			}
+			// Boolean flags don't take a separate value.
+			name := fmt.Sprintf("%s.%s", cmd.Receiver.Name, f.Full)
+			shared[name] = shared[name] || typ.Kind() != gofire.Bool
+		}
+	}
+	names := make([]string, 0, len(shared))
+	for name := range shared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]string, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, fmt.Sprintf("%q: %t,", name, shared[name]))
+	}
+	return strings.Join(entries, "\n")
+}
+
+func (p rproxy) Commands() []rcommand {
+	cmds := make([]rcommand, 0, len(p.tree.Commands))
+	for _, cmd := range p.tree.Commands {
+		expr := fmt.Sprintf("%s(ctx)", proxy{driver: p.driver, command: cmd}.Function())
+		// results are ignored except error unless they are printed by the main entrypoint.
+		code := discard(cmd, expr)
+		if p.Main() {
+			code = call(cmd, expr, "return %s")
+		}
+		cmds = append(cmds, rcommand{Name: names(cmd), Call: code})
+	}
+	return cmds
+}
+
+type rcommand struct {
+	Name string
+	Call string
+}
+
+const rtemplate = `
+	package {{.Package}}
+
+	import(
+		{{.Import}}
+	)
+
+	{{.Doc}}
+	func {{.Function}}(ctx context.Context) (err error) {
+		{{ if .Output }}
+			// extract the results output format out of the arguments list.
+			format, args, err := gofireoutput.Extract(os.Args)
+			if err != nil {
+				return err
+			}
+			os.Args = args
+		{{ end }}
+		help := func(w io.Writer) {
+			_, _ = fmt.Fprintln(w, {{.Help}})
+		}
+		{{ if .Shared }}
+			// shift the shared flags provided before the command name out of the arguments list,
+			// so they could be passed to the command along with the rest of its arguments.
+			var shared []string
+			for len(os.Args) > 2 && strings.HasPrefix(os.Args[1], "-") {
+				arg := strings.SplitN(strings.TrimLeft(os.Args[1], "-"), "=", 2)
+				value, ok := map[string]bool{
+					{{.Shared}}
+				}[arg[0]]
+				if !ok {
+					break
+				}
+				n := 1
+				if value && len(arg) == 1 {
+					n = 2
+				}
+				shared = append(shared, os.Args[1:n+1]...)
+				os.Args = append(os.Args[:1:1], os.Args[n+1:]...)
+			}
+		{{ end }}
+		if len(os.Args) < 2 {
+			help(os.Stderr)
+			return errors.New("command is required")
+		}
+		// shift the command name out of the arguments list
+		// so the command itself could parse the rest of them.
+		command := os.Args[1]
+		os.Args = append(os.Args[:1:1], os.Args[2:]...)
+		{{ if .Shared }}
+			os.Args = append(append(os.Args[:1:1], shared...), os.Args[1:]...)
+		{{ end }}
+		switch command {
+		{{ range .Commands }}
+			case {{.Name}}:
+				{{.Call}}
+		{{ end }}
+		case "help", "-help", "--help", "-h":
+			// requested help is not a failure, so it's printed to stdout without an error.
+			help(os.Stdout)
+		default:
+			help(os.Stderr)
+			return fmt.Errorf("command %q is unknown", command)
+		}
+		return
+	}
+
+	{{ if .Main }}
+		// auto generated main entrypoint.
+		func main() {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			{{ if .Printed }}
+				// the command has already printed its output instead of calling the function.
+			{{ end }}
+			if err := {{.Function}}(ctx); err != nil {{ if .Printed }} && !errors.Is(err, gofireoutput.ErrPrinted) {{ end }} {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+		}
+	{{ end }}
+`
@@ -0,0 +1,222 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	// Flag is the name of the option that holds the results output format.
+	Flag = "output"
+	// Text is the default output format, scalars are printed as is while structs, slices and maps are printed as tables.
+	Text = "text"
+	// JSON is the indented json output format.
+	JSON = "json"
+	// YAML is the block yaml output format.
+	YAML = "yaml"
+)
+
+// ErrPrinted is returned by the commands that have already printed their output instead of calling the function,
+// e.g. the effective configuration, so the caller should neither print the results nor treat it as a failure.
+var ErrPrinted = errors.New("output is already printed")
+
+// Extract extracts the output format option from the arguments e.g. --output=json or -output yaml
+// and returns the format along with the rest of the arguments, so the command itself never sees the option.
+// The option could be provided anywhere before the `--` terminator, text format is used by default.
+func Extract(args []string) (string, []string, error) {
+	format := Text
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if name == arg || !strings.HasPrefix(name, Flag) {
+			rest = append(rest, arg)
+			continue
+		}
+		switch value := strings.TrimPrefix(name, Flag); {
+		case strings.HasPrefix(value, "="):
+			format = value[1:]
+		case value == "" && i+1 < len(args):
+			i++
+			format = args[i]
+		case value == "":
+			return "", nil, fmt.Errorf("output format is required")
+		default:
+			// Other flags could share the option prefix e.g. --outputs.
+			rest = append(rest, arg)
+			continue
+		}
+		if format != Text && format != JSON && format != YAML {
+			return "", nil, fmt.Errorf("output format %q is not supported, one of %s, %s, %s is expected", format, Text, JSON, YAML)
+		}
+	}
+	return format, rest, nil
+}
+
+// Print writes the values to the writer in the output format. Multiple values are printed one after another
+// in text format and as a single list in json and yaml formats, so the output stays a single document.
+func Print(w io.Writer, format string, values ...interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	var v interface{} = values
+	if len(values) == 1 {
+		v = values[0]
+	}
+	switch format {
+	case Text, "":
+		for _, v := range values {
+			if err := text(w, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case JSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("output can't be encoded to json, %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case YAML:
+		// Values are encoded through json first, so json tags and marshalers are respected.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("output can't be encoded to yaml, %w", err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		tree, err := ordered(dec)
+		if err != nil {
+			return fmt.Errorf("output can't be encoded to yaml, %w", err)
+		}
+		out, err := yaml(tree)
+		if err != nil {
+			return fmt.Errorf("output can't be encoded to yaml, %w", err)
+		}
+		_, err = fmt.Fprintln(w, out)
+		return err
+	default:
+		return fmt.Errorf("output format %q is not supported", format)
+	}
+}
+
+// text writes the value in text format, structs are printed as a single row table with a column per exported field,
+// slices and arrays are printed as a table with a row per element and maps as a table with a row per key.
+func text(w io.Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && !scalar(rv) {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || scalar(rv) {
+		_, err := fmt.Fprintln(w, v)
+		return err
+	}
+	var rows [][]string
+	switch rv.Kind() {
+	case reflect.Struct:
+		header, row := columns(rv)
+		// Structs without exported fields have nothing to tabulate.
+		if len(header) == 0 {
+			_, err := fmt.Fprintln(w, v)
+			return err
+		}
+		rows = append(rows, header, row)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			el := rv.Index(i)
+			for el.Kind() == reflect.Ptr && !el.IsNil() && !scalar(el) {
+				el = el.Elem()
+			}
+			if el.Kind() != reflect.Struct || scalar(el) {
+				rows = append(rows, []string{cell(el)})
+				continue
+			}
+			header, row := columns(el)
+			if i == 0 {
+				rows = append(rows, header)
+			}
+			rows = append(rows, row)
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		cells := make([]string, 0, len(keys))
+		byCell := make(map[string]reflect.Value, len(keys))
+		for _, key := range keys {
+			c := cell(key)
+			cells = append(cells, c)
+			byCell[c] = rv.MapIndex(key)
+		}
+		// Maps are printed sorted by keys, so the output is stable.
+		sort.Strings(cells)
+		rows = append(rows, []string{"KEY", "VALUE"})
+		for _, c := range cells {
+			rows = append(rows, []string{c, cell(byCell[c])})
+		}
+	default:
+		_, err := fmt.Fprintln(w, v)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// columns returns the struct table header and row, a column per exported struct field.
+func columns(rv reflect.Value) ([]string, []string) {
+	var header, row []string
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		header = append(header, strings.ToUpper(f.Name))
+		row = append(row, cell(rv.Field(i)))
+	}
+	return header, row
+}
+
+// cell returns the value formatted for a table cell, pointers are dereferenced.
+func cell(rv reflect.Value) string {
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && !scalar(rv) {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || !rv.CanInterface() {
+		return ""
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// scalar reports whether the value is printed as is, values that know how to format themselves are scalars too.
+func scalar(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return true
+	}
+	if rv.CanInterface() {
+		switch rv.Interface().(type) {
+		case fmt.Stringer, error:
+			return true
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+		return false
+	default:
+		return true
+	}
+}
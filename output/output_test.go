@@ -0,0 +1,141 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	table := map[string]struct {
+		args   []string
+		format string
+		rest   []string
+		err    error
+	}{
+		"no output option should produce default format": {
+			args:   []string{"cmd", "-a=1", "arg"},
+			format: Text,
+			rest:   []string{"cmd", "-a=1", "arg"},
+		},
+		"output option with value should produce expected format": {
+			args:   []string{"cmd", "--output=json", "arg"},
+			format: JSON,
+			rest:   []string{"cmd", "arg"},
+		},
+		"output option with separate value should produce expected format": {
+			args:   []string{"cmd", "arg", "-output", "yaml"},
+			format: YAML,
+			rest:   []string{"cmd", "arg"},
+		},
+		"output option after terminator should be kept": {
+			args:   []string{"cmd", "--", "--output=json"},
+			format: Text,
+			rest:   []string{"cmd", "--", "--output=json"},
+		},
+		"output prefixed flags should be kept": {
+			args:   []string{"cmd", "--outputs=1"},
+			format: Text,
+			rest:   []string{"cmd", "--outputs=1"},
+		},
+		"output option without value should produce expected error": {
+			args: []string{"cmd", "--output"},
+			err:  errors.New("output format is required"),
+		},
+		"unsupported output format should produce expected error": {
+			args: []string{"cmd", "--output=xml"},
+			err:  errors.New(`output format "xml" is not supported, one of text, json, yaml is expected`),
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			format, rest, err := Extract(tcase.args)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if tcase.format != format {
+				t.Fatalf("expected format %q but got %q", tcase.format, format)
+			}
+			if !reflect.DeepEqual(tcase.rest, rest) {
+				t.Fatalf("expected rest arguments %v but got %v", tcase.rest, rest)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	type user struct {
+		Name  string   `json:"name"`
+		Age   int      `json:"age"`
+		Tags  []string `json:"tags"`
+		email string
+	}
+	users := []*user{{Name: "alice", Age: 30, Tags: []string{"admin"}}, {Name: "bob", Age: 7}}
+	table := map[string]struct {
+		format string
+		values []interface{}
+		out    string
+		err    error
+	}{
+		"scalar values should produce expected text output": {
+			format: Text,
+			values: []interface{}{10, "foo", 2 * time.Second},
+			out:    "10\nfoo\n2s\n",
+		},
+		"struct value should produce expected text output": {
+			format: Text,
+			values: []interface{}{users[0]},
+			out:    "NAME   AGE  TAGS\nalice  30   [admin]\n",
+		},
+		"slice value should produce expected text output": {
+			format: Text,
+			values: []interface{}{users},
+			out:    "NAME   AGE  TAGS\nalice  30   [admin]\nbob    7    []\n",
+		},
+		"map value should produce expected text output": {
+			format: Text,
+			values: []interface{}{map[string]int{"b": 2, "a": 1}},
+			out:    "KEY  VALUE\na    1\nb    2\n",
+		},
+		"multiple values should produce expected json output": {
+			format: JSON,
+			values: []interface{}{1, users[1]},
+			out:    "[\n  1,\n  {\n    \"name\": \"bob\",\n    \"age\": 7,\n    \"tags\": null\n  }\n]\n",
+		},
+		"slice value should produce expected yaml output": {
+			format: YAML,
+			values: []interface{}{users},
+			out:    "- name: alice\n  age: 30\n  tags:\n    - admin\n- name: bob\n  age: 7\n  tags: null\n",
+		},
+		"ambiguous strings should produce expected yaml output": {
+			format: YAML,
+			values: []interface{}{map[string]interface{}{"a": "true", "b": "", "c": "10", "d": "foo bar", "e": map[string]int{}}},
+			out:    "a: \"true\"\nb: \"\"\nc: \"10\"\nd: foo bar\ne: {}\n",
+		},
+		"special strings should produce expected yaml output": {
+			format: YAML,
+			values: []interface{}{map[string]interface{}{"a": "x: y", "b": "line\nnext", "c": "- item", "d": "#tag", "e: f": 1}},
+			out:    "a: \"x: y\"\nb: \"line\\nnext\"\nc: \"- item\"\nd: \"#tag\"\n\"e: f\": 1\n",
+		},
+		"unsupported format should produce expected error": {
+			format: "xml",
+			values: []interface{}{1},
+			err:    errors.New(`output format "xml" is not supported`),
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Print(&buf, tcase.format, tcase.values...)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if tcase.out != buf.String() {
+				t.Fatalf("expected output %q but got %q", tcase.out, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yentry holds a single yaml mapping entry, mappings are kept as lists of entries to preserve the keys order.
+type yentry struct {
+	key   string
+	value interface{}
+}
+
+// ordered decodes the json value into the tree of entries lists, lists and scalars preserving the objects keys order.
+func ordered(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return t, nil
+	}
+	switch delim {
+	case '{':
+		entries := []yentry{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := ordered(dec)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, yentry{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return entries, err
+	case '[':
+		list := []interface{}{}
+		for dec.More() {
+			value, err := ordered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	default:
+		return nil, fmt.Errorf("unexpected json delimiter %s", delim)
+	}
+}
+
+// yaml encodes the ordered tree to block yaml, empty mappings and lists are encoded in flow style.
+// The tree is decoded from json, so only json values are supported and any other value is reported as an error.
+// Strings are either written plain or double quoted with go escapes, which are valid yaml escapes as well.
+func yaml(v interface{}) (string, error) {
+	lines, err := yblock(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func yblock(v interface{}) ([]string, error) {
+	var lines []string
+	switch tv := v.(type) {
+	case []yentry:
+		if len(tv) == 0 {
+			return []string{"{}"}, nil
+		}
+		for _, e := range tv {
+			key := yscalar(e.key)
+			block, err := yblock(e.value)
+			if err != nil {
+				return nil, err
+			}
+			if yinline(e.value) {
+				lines = append(lines, fmt.Sprintf("%s: %s", key, block[0]))
+				continue
+			}
+			lines = append(lines, key+":")
+			for _, line := range block {
+				lines = append(lines, "  "+line)
+			}
+		}
+	case []interface{}:
+		if len(tv) == 0 {
+			return []string{"[]"}, nil
+		}
+		for _, item := range tv {
+			block, err := yblock(item)
+			if err != nil {
+				return nil, err
+			}
+			for i, line := range block {
+				if i == 0 {
+					lines = append(lines, "- "+line)
+				} else {
+					lines = append(lines, "  "+line)
+				}
+			}
+		}
+	case nil:
+		lines = append(lines, "null")
+	case bool:
+		lines = append(lines, strconv.FormatBool(tv))
+	case json.Number:
+		lines = append(lines, tv.String())
+	case string:
+		lines = append(lines, yscalar(tv))
+	default:
+		return nil, fmt.Errorf("unsupported yaml value %v of type %T", tv, tv)
+	}
+	return lines, nil
+}
+
+// yinline reports whether the value is written on the same line as its key.
+func yinline(v interface{}) bool {
+	switch tv := v.(type) {
+	case []yentry:
+		return len(tv) == 0
+	case []interface{}:
+		return len(tv) == 0
+	default:
+		return true
+	}
+}
+
+// yplain matches the strings that could be written without quotes.
+var yplain = regexp.MustCompile(`^[\pL_/][\pL\pN_./ -]*$`)
+
+// yscalar quotes the string unless it's a plain yaml scalar that can't be mistaken for other types.
+func yscalar(s string) string {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n":
+		return strconv.Quote(s)
+	}
+	if !yplain.MatchString(s) || strings.HasSuffix(s, " ") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package parsers
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/1pkg/gofire"
+)
+
+// constrain parses the constraint tag e.g. min=1 or pattern='^[a-z]+$' accordingly to the flag type
+// and sets it to the flag, the values are kept in their canonical text form so they could be used
+// in the generated code. Ranges are validated as soon as both of their bounds are set.
+func constrain(f *gofire.Flag, tag string) error {
+	tv := strings.SplitN(tag, "=", 2)
+	key, literal := strings.TrimSpace(tv[0]), strings.TrimSpace(tv[1])
+	typ := elem(f.Type)
+	switch key {
+	case "min", "max":
+		switch typ.Kind() {
+		case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
+		case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
+		case gofire.Float32, gofire.Float64:
+		default:
+			return fmt.Errorf("%s is not supported for type %s", key, f.Type.Type())
+		}
+		v, _, err := ParseTypeValue(typ, unquote(strings.ReplaceAll(literal, `'`, `"`)))
+		if err != nil {
+			return fmt.Errorf("%s value %v", key, err)
+		}
+		if key == "min" {
+			f.Min = fmt.Sprint(v)
+		} else {
+			f.Max = fmt.Sprint(v)
+		}
+		if f.Min != "" && f.Max != "" && bound(typ, f.Max) < bound(typ, f.Min) {
+			return fmt.Errorf("min %s is greater than max %s", f.Min, f.Max)
+		}
+	case "pattern":
+		if typ.Kind() != gofire.String {
+			return fmt.Errorf("%s is not supported for type %s", key, f.Type.Type())
+		}
+		// Patterns are kept raw, so the backslashes don't need to be escaped.
+		if len(literal) > 1 && strings.HasPrefix(literal, `'`) && strings.HasSuffix(literal, `'`) {
+			literal = literal[1 : len(literal)-1]
+		}
+		if _, err := regexp.Compile(literal); err != nil {
+			return fmt.Errorf("%s value %v", key, err)
+		}
+		f.Pattern = literal
+	case "minlen", "maxlen":
+		switch typ.Kind() {
+		case gofire.String, gofire.Slice, gofire.Map:
+		default:
+			return fmt.Errorf("%s is not supported for type %s", key, f.Type.Type())
+		}
+		v, err := strconv.ParseUint(unquote(strings.ReplaceAll(literal, `'`, `"`)), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s value %v", key, err)
+		}
+		if key == "minlen" {
+			f.MinLen = fmt.Sprint(v)
+		} else {
+			f.MaxLen = fmt.Sprint(v)
+		}
+		if f.MinLen != "" && f.MaxLen != "" && size(f.MaxLen) < size(f.MinLen) {
+			return fmt.Errorf("minlen %s is greater than maxlen %s", f.MinLen, f.MaxLen)
+		}
+	}
+	return nil
+}
+
+// satisfy checks that the parsed flag value satisfies the flag constraints.
+func satisfy(f gofire.Flag, v interface{}) error {
+	typ := elem(f.Type)
+	if f.Min != "" && number(v) < bound(typ, f.Min) {
+		return fmt.Errorf("%v is less than min %s", v, f.Min)
+	}
+	if f.Max != "" && number(v) > bound(typ, f.Max) {
+		return fmt.Errorf("%v is greater than max %s", v, f.Max)
+	}
+	if s, ok := v.(string); ok && f.Pattern != "" && !regexp.MustCompile(f.Pattern).MatchString(s) {
+		return fmt.Errorf("%q doesn't match pattern %s", s, f.Pattern)
+	}
+	if f.MinLen != "" || f.MaxLen != "" {
+		l := length(v)
+		if f.MinLen != "" && l < size(f.MinLen) {
+			return fmt.Errorf("%v length %d is less than minlen %s", v, l, f.MinLen)
+		}
+		if f.MaxLen != "" && l > size(f.MaxLen) {
+			return fmt.Errorf("%v length %d is greater than maxlen %s", v, l, f.MaxLen)
+		}
+	}
+	return nil
+}
+
+// bound parses the canonical range constraint value accordingly to the type,
+// the value is converted to float so values of any numeric kind could be compared.
+func bound(typ gofire.Typ, literal string) float64 {
+	v, _, _ := ParseTypeValue(typ, literal)
+	return number(v)
+}
+
+func number(v interface{}) float64 {
+	switch tv := v.(type) {
+	case int64:
+		return float64(tv)
+	case uint64:
+		return float64(tv)
+	case float64:
+		return tv
+	default:
+		return 0
+	}
+}
+
+// size parses the canonical length constraint value.
+func size(literal string) int {
+	v, _ := strconv.Atoi(literal)
+	return v
+}
+
+func length(v interface{}) int {
+	switch tv := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return utf8.RuneCountInString(tv)
+	default:
+		return reflect.ValueOf(v).Len()
+	}
+}
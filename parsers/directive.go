@@ -0,0 +1,270 @@
+package parsers
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/1pkg/gofire"
+)
+
+// directive holds the function doc directive for a single function parameter e.g.
+// //gofire:param c short=c default=2.0 doc='multiplier' or //gofire:arg a name=left default=1.
+type directive struct {
+	kind string
+	tags []string
+	doc  string
+	name string
+	// dval holds the raw default value of the optional argument, dset reports whether it's set.
+	dval string
+	dset bool
+}
+
+// directives parses the function doc directives keyed by the parameter name,
+// note that directives are naturally excluded from the function doc text.
+func directives(doc *ast.CommentGroup) (map[string]directive, error) {
+	dirs := make(map[string]directive)
+	if doc == nil {
+		return dirs, nil
+	}
+	for _, c := range doc.List {
+		tokens := tokenize(strings.TrimPrefix(c.Text, "//"))
+		// Command directives are parsed separately, see metadata.
+		if len(tokens) == 0 || tokens[0] == "command" {
+			continue
+		}
+		if len(tokens) < 2 || (tokens[0] != "param" && tokens[0] != "arg") {
+			return nil, fmt.Errorf("can't parse directive %s unsupported directive", c.Text)
+		}
+		d := directive{kind: tokens[0]}
+		param := tokens[1]
+		if _, ok := dirs[param]; ok {
+			return nil, fmt.Errorf("can't parse directive %s duplicated parameter %s directive", c.Text, param)
+		}
+		for _, tag := range tokens[2:] {
+			tv := strings.SplitN(tag, "=", 2)
+			switch {
+			case tv[0] == "doc" && len(tv) == 2:
+				d.doc = unquoteq(tv[1])
+			case tv[0] == "name" && len(tv) == 2:
+				name := unquoteq(tv[1])
+				what := "argument"
+				if d.kind == "param" {
+					what = "flag"
+				}
+				for _, r := range name {
+					if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+						return nil, fmt.Errorf("can't parse directive %s %s name %s is not alphanumeric", c.Text, what, name)
+					}
+				}
+				d.name = name
+			case tv[0] == "default" && len(tv) == 2 && d.kind == "arg":
+				d.dval, d.dset = unquoteq(tv[1]), true
+			case d.kind == "param":
+				// The rest of the param directive keys are validated later by the flag tag rules.
+				d.tags = append(d.tags, tag)
+			default:
+				return nil, fmt.Errorf("can't parse directive %s unsupported %q key", c.Text, tv[0])
+			}
+		}
+		dirs[param] = d
+	}
+	return dirs, nil
+}
+
+// metadata parses the function doc command directive e.g. //gofire:command name=sync-users aliases=su silent
+// and applies it to the command, the directive could be split into multiple lines.
+func metadata(cmd *gofire.Command, doc *ast.CommentGroup) error {
+	if doc == nil {
+		return nil
+	}
+	for _, c := range doc.List {
+		tokens := tokenize(strings.TrimPrefix(c.Text, "//"))
+		if len(tokens) == 0 || tokens[0] != "command" {
+			continue
+		}
+		for _, tag := range tokens[1:] {
+			tv := strings.SplitN(tag, "=", 2)
+			if len(tv) != 2 && tv[0] != "hidden" && tv[0] != "silent" {
+				return fmt.Errorf("can't parse directive %s missing %q key value", c.Text, tv[0])
+			}
+			switch tv[0] {
+			case "name":
+				name := unquoteq(tv[1])
+				if err := cname(name); err != nil {
+					return fmt.Errorf("can't parse directive %s command %w", c.Text, err)
+				}
+				cmd.Name = name
+			case "aliases":
+				for _, alias := range strings.Split(unquoteq(tv[1]), ",") {
+					alias = strings.TrimSpace(alias)
+					if err := cname(alias); err != nil {
+						return fmt.Errorf("can't parse directive %s command alias %w", c.Text, err)
+					}
+					cmd.Aliases = append(cmd.Aliases, alias)
+				}
+			case "long":
+				cmd.Long = unquoteq(tv[1])
+			case "example":
+				cmd.Examples = append(cmd.Examples, unquoteq(tv[1]))
+			case "hidden", "silent":
+				v := true
+				if len(tv) == 2 {
+					var err error
+					if v, err = strconv.ParseBool(tv[1]); err != nil {
+						return fmt.Errorf("can't parse directive %s as boolean for %q key and %s value", c.Text, tv[0], tv[1])
+					}
+				}
+				if tv[0] == "hidden" {
+					cmd.Hidden = v
+				} else {
+					cmd.Silent = v
+				}
+			default:
+				return fmt.Errorf("can't parse directive %s unsupported %q key", c.Text, tv[0])
+			}
+		}
+	}
+	return nil
+}
+
+// cname checks that the command name could be typed in the command line.
+func cname(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	for _, r := range name {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+			return fmt.Errorf("name %s is not alphanumeric", name)
+		}
+	}
+	return nil
+}
+
+// tokenize splits the directive into space separated tokens respecting braces and single quotes.
+func tokenize(text string) []string {
+	if !strings.HasPrefix(text, "gofire:") {
+		return nil
+	}
+	var tokens []string
+	for _, t := range joinq(splitb(strings.TrimPrefix(text, "gofire:"), " ", "{", "}"), " ") {
+		if t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// dflag builds the pointer flag parameter with the param directive applied,
+// the directive keys are validated by the same rules as the flag tags.
+func (p parser) dflag(name string, typ gofire.Typ, d directive) (*gofire.Flag, error) {
+	if d.kind != "param" {
+		return nil, fmt.Errorf("%s directive is not supported for flag parameter %s", d.kind, name)
+	}
+	var tag string
+	if len(d.tags) > 0 {
+		tag = `gofire:"` + strings.Join(d.tags, ",") + `"`
+	}
+	f, opts, err := p.tagflag(typ, tag)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s directive can't be parsed, %w", name, err)
+	}
+	if opts.nested {
+		return nil, fmt.Errorf("parameter %s directive can't be parsed, nested is not supported for flag parameters", name)
+	}
+	f.Full = name
+	if d.name != "" {
+		f.Full = d.name
+	}
+	f.Doc = d.doc
+	// Fix broken default in case the flag wasn't set.
+	if !opts.set {
+		f.Default = elem(typ).Kind().Default()
+	}
+	return f, nil
+}
+
+// unquoteq strips the single quotes around the directive value if they are provided.
+func unquoteq(v string) string {
+	if len(v) > 1 && strings.HasPrefix(v, `'`) && strings.HasSuffix(v, `'`) {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// params matches the function doc parameters section item e.g. `left: the left operand`.
+var params = regexp.MustCompile(`^([\pL_][\pL\pN_]*)\s*[:-]\s*(.*)$`)
+
+// section extracts the parameters docs from the function doc `Parameters:` section
+// and returns the function doc without the section.
+func section(doc string) (string, map[string]string) {
+	docs := make(map[string]string)
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "Parameters:" {
+			continue
+		}
+		var last string
+		end := i + 1
+		for ; end < len(lines); end++ {
+			item := strings.TrimSpace(lines[end])
+			// Gofmt separates indented items from the section header with a blank line.
+			if item == "" && end == i+1 {
+				continue
+			}
+			if item == "" {
+				break
+			}
+			if m := params.FindStringSubmatch(item); m != nil {
+				last = m[1]
+				docs[last] = m[2]
+				continue
+			}
+			// Items could be continued on the next lines.
+			if last != "" {
+				docs[last] = strings.TrimSpace(docs[last] + " " + item)
+			}
+		}
+		// The blank line that ends the section is dropped along with it.
+		if end < len(lines) {
+			end++
+		}
+		doc = strings.TrimSpace(strings.Join(append(lines[:i:i], lines[end:]...), "\n"))
+		break
+	}
+	return doc, docs
+}
+
+// inline extracts the parameters docs from the inline comments in the function signature
+// e.g. `func add(left /* the left operand */ int, right int /* the right operand */)`.
+func inline(f file, fdecl *ast.FuncDecl) map[string]string {
+	docs := make(map[string]string)
+	if fdecl.Type.Params == nil {
+		return docs
+	}
+	list := fdecl.Type.Params.List
+	for i, param := range list {
+		// Comments are attributed to the parameter up to the next parameter start.
+		end := fdecl.Type.Params.Closing
+		if i+1 < len(list) {
+			end = list[i+1].Pos()
+		}
+		var texts []string
+		for _, c := range f.ast.Comments {
+			if c.Pos() > param.Pos() && c.End() <= end {
+				if text := strings.TrimSpace(c.Text()); text != "" {
+					texts = append(texts, text)
+				}
+			}
+		}
+		for _, name := range param.Names {
+			if len(texts) > 0 {
+				docs[name.Name] = strings.Join(texts, " ")
+			}
+		}
+	}
+	return docs
+}
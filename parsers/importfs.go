@@ -0,0 +1,73 @@
+package parsers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ImportFS defines package fs that is also capable of resolving imported packages.
+type ImportFS interface {
+	fs.FS
+	// Import returns fs and name of the package for provided import path.
+	Import(ctx context.Context, path string) (fs.FS, string, error)
+}
+
+// DirFS returns package fs for provided directory that resolves imported packages
+// with module aware go tool lookup against the local module cache or vendor directory.
+func DirFS(dir string) ImportFS {
+	return dirfs{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirfs struct {
+	fs.FS
+	dir string
+}
+
+func (d dirfs) Import(ctx context.Context, path string) (fs.FS, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "list", "-find", "-f", "{{.Dir}}\n{{.Name}}", path)
+	cmd.Dir = d.dir
+	// Never reach the network, imported packages have to be available locally.
+	cmd.Env = append(os.Environ(), "GOPROXY=off")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, "", fmt.Errorf("package %s can't be imported, %v %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	out := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(out) != 2 {
+		return nil, "", fmt.Errorf("package %s can't be imported, unexpected go list output %q", path, stdout.String())
+	}
+	return DirFS(out[0]), out[1], nil
+}
+
+// importer loads and caches imported packages.
+type importer struct {
+	ctx      context.Context
+	dir      fs.FS
+	packages map[string]*pckgast
+}
+
+func (i importer) load(path string) (*pckgast, error) {
+	if pckg, ok := i.packages[path]; ok {
+		return pckg, nil
+	}
+	ifs, ok := i.dir.(ImportFS)
+	if !ok {
+		return nil, fmt.Errorf("package %s can't be imported, fs doesn't support imports", path)
+	}
+	dir, name, err := ifs.Import(i.ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	pckg, err := load(i.ctx, dir, name, path)
+	if err != nil {
+		return nil, err
+	}
+	i.packages[path] = pckg
+	return pckg, nil
+}
@@ -7,6 +7,7 @@ import (
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
+	"go/types"
 	"io/fs"
 	"log"
 	"strconv"
@@ -18,12 +19,150 @@ import (
 
 // Parse tries to parse the function from provided ast into command type.
 func Parse(ctx context.Context, dir fs.FS, pckg, function string) (*gofire.Command, error) {
+	tree, err := ParseTree(ctx, dir, pckg, function)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.Commands[0], nil
+}
+
+// ParseTree tries to parse the functions from provided ast into command tree type.
+// Besides plain function names it also accepts methods in form of `Type.Method`
+// and whole types in which case all exported type methods become commands.
+func ParseTree(ctx context.Context, dir fs.FS, pckg string, functions ...string) (*gofire.Tree, error) {
+	pckgast, err := load(ctx, dir, pckg, "")
+	if err != nil {
+		return nil, err
+	}
+	tree := gofire.Tree{
+		Package: pckg,
+		Name:    pckg,
+		Doc:     pckgast.doc,
+	}
+	parsed := make(map[string]bool, len(functions))
+	for _, function := range functions {
+		if parsed[function] {
+			return nil, fmt.Errorf("function %s is duplicated in ast package %s", function, pckg)
+		}
+		parsed[function] = true
+		// In case the function is not found, but there is a type
+		// with the same name expand it into all exported type methods.
+		methods, ok := pckgast.methods[function]
+		if _, fok := pckgast.fdecls[function]; fok || !ok {
+			cmd, err := pckgast.command(function)
+			if err != nil {
+				return nil, err
+			}
+			tree.Commands = append(tree.Commands, *cmd)
+			continue
+		}
+		var exported bool
+		for _, method := range methods {
+			if !ast.IsExported(method) {
+				continue
+			}
+			cmd, err := pckgast.command(fmt.Sprintf("%s.%s", function, method))
+			if err != nil {
+				return nil, err
+			}
+			tree.Commands = append(tree.Commands, *cmd)
+			exported = true
+		}
+		if !exported {
+			return nil, fmt.Errorf("type %s has no exported methods in ast package %s", function, pckg)
+		}
+		// For a single type use the type itself as the tree root.
+		if len(functions) == 1 {
+			tree.Name = function
+			if g, ok := pckgast.parser.groups[function]; ok {
+				tree.Doc = g.Doc
+			}
+		}
+	}
+	if len(tree.Commands) == 0 {
+		return nil, fmt.Errorf("no functions provided for ast package %s", pckg)
+	}
+	return &tree, nil
+}
+
+// ParsePackage tries to parse all exported functions from provided ast into command tree type.
+// Functions that can't be parsed are skipped and reported instead of failing the whole package.
+func ParsePackage(ctx context.Context, dir fs.FS, pckg string) (*gofire.Tree, error) {
+	pckgast, err := load(ctx, dir, pckg, "")
+	if err != nil {
+		return nil, err
+	}
+	tree := gofire.Tree{
+		Package: pckg,
+		Name:    pckg,
+		Doc:     pckgast.doc,
+	}
+	for _, function := range pckgast.functions {
+		if !ast.IsExported(function) {
+			continue
+		}
+		cmd, err := pckgast.command(function)
+		if err != nil {
+			// in case function can't be parsed just skip it.
+			log.Printf("function %s is skipped, %v", function, err)
+			continue
+		}
+		tree.Commands = append(tree.Commands, *cmd)
+	}
+	if len(tree.Commands) == 0 {
+		return nil, fmt.Errorf("no exported functions can be parsed in ast package %s", pckg)
+	}
+	return &tree, nil
+}
+
+type pckgast struct {
+	name      string
+	doc       string
+	parser    parser
+	files     []file
+	functions []string
+	methods   map[string][]string
+	fdecls    map[string]fdecl
+}
+
+type fdecl struct {
+	file file
+	decl *ast.FuncDecl
+}
+
+// generated is the header of the files generated by gofire itself.
+const generated = "// THIS IS AUTOGENERATED FILE."
+
+// pdoc reports whether the file doc is the conventional package doc.
+func pdoc(doc, pckg string) bool {
+	return strings.HasPrefix(doc, "Package "+pckg) || strings.HasPrefix(doc, "Command ")
+}
+
+// load parses all package files from provided fs driver
+// and visits all package types and functions declarations.
+// Import path is provided only for imported packages, so their types are qualified.
+func load(ctx context.Context, dir fs.FS, pckg, path string) (*pckgast, error) {
 	// Start with parsing actual ast from fs driver.
 	fentries, err := fs.ReadDir(dir, ".")
 	if err != nil {
 		return nil, fmt.Errorf("ast package %s fs dir can't be read, %w", pckg, err)
 	}
-	var files []file
+	pckgast := pckgast{
+		name: pckg,
+		parser: parser{
+			pckg:      pckg,
+			path:      path,
+			groups:    make(map[string]gofire.Group),
+			types:     make(map[string]tdecl),
+			funcs:     make(map[string]*ast.FuncDecl),
+			consts:    make(map[string][]string),
+			failed:    make(map[string]error),
+			resolving: make(map[string]bool),
+			importer:  importer{ctx: ctx, dir: dir, packages: make(map[string]*pckgast)},
+		},
+		methods: make(map[string][]string),
+		fdecls:  make(map[string]fdecl),
+	}
 	fset := token.NewFileSet()
 	for _, fentry := range fentries {
 		fname := fentry.Name()
@@ -34,6 +173,10 @@ func Parse(ctx context.Context, dir fs.FS, pckg, function string) (*gofire.Comma
 		if err != nil {
 			return nil, fmt.Errorf("ast file %s in package %s fs file can't be read, %w", fname, pckg, err)
 		}
+		// Previously generated cli files are skipped, so generated commands never become commands themselves.
+		if bytes.HasPrefix(b, []byte(generated)) {
+			continue
+		}
 		buf := bytes.NewBuffer(b)
 		f, err := goparser.ParseFile(fset, "", buf, goparser.AllErrors|goparser.ParseComments)
 		if err != nil {
@@ -42,20 +185,43 @@ func Parse(ctx context.Context, dir fs.FS, pckg, function string) (*gofire.Comma
 		if f.Name.Name != pckg {
 			continue
 		}
-		files = append(files, file{fset: fset, fname: fname, ast: f, buf: buf})
+		// The conventional `Package name` doc takes precedence over other files docs.
+		if doc := strings.TrimSpace(f.Doc.Text()); doc != "" && (pckgast.doc == "" || pdoc(doc, pckg) && !pdoc(pckgast.doc, pckg)) {
+			pckgast.doc = doc
+		}
+		pckgast.files = append(pckgast.files, file{fset: fset, fname: fname, ast: f, buf: buf})
 	}
-	// Now as ast is parsed successfully parse it into command.
-	p := make(parser)
-	var fparse func(context.Context) (*gofire.Command, error)
-	for _, file := range files {
-		// Visit all types inide the package to build flag groups.
+	// Type check the package to resolve types precisely when it's possible.
+	pckgast.parser.info = check(dir, fset, pckgast.parser.tpath(), pckgast.files)
+	// Collect all named types, constants and functions declarations first, so they could be resolved
+	// regardless of the declaration order.
+	for _, file := range pckgast.files {
 		for _, decl := range file.ast.Decls {
+			if gdecl, ok := decl.(*ast.GenDecl); ok {
+				for _, spec := range gdecl.Specs {
+					if tspec, ok := spec.(*ast.TypeSpec); ok {
+						pckgast.parser.types[tspec.Name.Name] = tdecl{file: file, gdecl: gdecl, tspec: tspec}
+					}
+				}
+				if gdecl.Tok == token.CONST {
+					pckgast.parser.constants(gdecl)
+				}
+			}
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil {
+				pckgast.parser.funcs[fd.Name.Name] = fd
+			}
+		}
+	}
+	// Now as ast is parsed successfully visit all its declarations.
+	for _, file := range pckgast.files {
+		for _, decl := range file.ast.Decls {
+			// Visit all types inide the package to build flag groups.
 			gdecl, ok := decl.(*ast.GenDecl)
 			if ok {
 				for _, spec := range gdecl.Specs {
 					tspec, ok := spec.(*ast.TypeSpec)
 					if ok {
-						if err := p.register(file, gdecl, tspec); err != nil {
+						if err := pckgast.parser.register(file, gdecl, tspec); err != nil {
 							// in case type can't be parsed just skip it.
 							log.Print(err)
 							continue
@@ -63,38 +229,146 @@ func Parse(ctx context.Context, dir fs.FS, pckg, function string) (*gofire.Comma
 					}
 				}
 			}
-			// In case we found function declaration that we need - save it,
+			// In case we found function or method declaration - save it,
 			// we will process it later after the visit loop.
-			file := file
-			if fdecl, ok := decl.(*ast.FuncDecl); ok && fdecl.Name.Name == function {
-				fparse = func(context.Context) (*gofire.Command, error) {
-					var cmd gofire.Command
-					cmd.Package = pckg
-					cmd.Function = function
-					cmd.Definition = file.definition(fdecl.Pos(), fdecl.Type.End())
-					cmd.Doc = strings.TrimSpace(fdecl.Doc.Text())
-					cmd.Results = p.results(file, fdecl)
-					params, context, err := p.parameters(file, fdecl)
-					if err != nil {
-						return nil, fmt.Errorf(
-							"ast file %s in package %s function %s ast parsing error, %w",
-							file.fname,
-							pckg,
-							function,
-							err,
-						)
-					}
-					cmd.Context = context
-					cmd.Parameters = params
-					return &cmd, nil
-				}
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if fd.Recv == nil {
+				pckgast.functions = append(pckgast.functions, fd.Name.Name)
+				pckgast.fdecls[fd.Name.Name] = fdecl{file: file, decl: fd}
+				continue
 			}
+			if typ, ok := receiver(fd); ok {
+				pckgast.methods[typ] = append(pckgast.methods[typ], fd.Name.Name)
+				pckgast.fdecls[fmt.Sprintf("%s.%s", typ, fd.Name.Name)] = fdecl{file: file, decl: fd}
+			}
+		}
+	}
+	return &pckgast, nil
+}
+
+// command tries to parse the function from visited package into command type.
+func (pckg pckgast) command(function string) (*gofire.Command, error) {
+	cmd, err := pckg.build(function, nil)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Chain = pckg.chain(pckg.fdecls[function].decl)
+	return cmd, nil
+}
+
+// build tries to parse the function or method from visited package into command type.
+// For methods the receiver is built from the receiver type flags group unless it's provided.
+func (pckg pckgast) build(function string, recv *gofire.Group) (*gofire.Command, error) {
+	fd, ok := pckg.fdecls[function]
+	if !ok {
+		return nil, fmt.Errorf("function %s can't be found in ast package %s", function, pckg.name)
+	}
+	file, fdecl := fd.file, fd.decl
+	p := pckg.parser
+	var cmd gofire.Command
+	cmd.Package = pckg.name
+	cmd.Function = fdecl.Name.Name
+	if fdecl.Recv != nil && recv == nil {
+		var err error
+		if recv, err = p.receiver(file, fdecl); err != nil {
+			return nil, fmt.Errorf(
+				"ast file %s in package %s method %s ast parsing error, %w",
+				file.fname,
+				pckg.name,
+				function,
+				err,
+			)
+		}
+	}
+	cmd.Receiver = recv
+	cmd.Definition = file.definition(fdecl.Pos(), fdecl.Type.End())
+	// Parameters section is shown along with the parameters, so it's excluded from the command doc.
+	cmd.Doc, _ = section(strings.TrimSpace(fdecl.Doc.Text()))
+	if err := metadata(&cmd, fdecl.Doc); err != nil {
+		return nil, fmt.Errorf(
+			"ast file %s in package %s function %s ast parsing error, %w",
+			file.fname,
+			pckg.name,
+			function,
+			err,
+		)
+	}
+	cmd.Results = p.results(file, fdecl)
+	params, context, err := p.parameters(file, fdecl)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"ast file %s in package %s function %s ast parsing error, %w",
+			file.fname,
+			pckg.name,
+			function,
+			err,
+		)
+	}
+	cmd.Context = context
+	cmd.Parameters = params
+	return &cmd, nil
+}
+
+// chain tries to parse exported methods of the function first result named type into chained commands.
+// The receiver of chained commands is not parsed from flags, it's provided by the function result instead.
+func (pckg pckgast) chain(fdecl *ast.FuncDecl) (chain []gofire.Command) {
+	if fdecl.Type.Results == nil || len(fdecl.Type.Results.List) == 0 {
+		return nil
+	}
+	tp := fdecl.Type.Results.List[0].Type
+	star, ptr := tp.(*ast.StarExpr)
+	if ptr {
+		tp = star.X
+	}
+	id, ok := tp.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	var typ gofire.Typ = gofire.TStruct{Typ: id.Name}
+	if ptr {
+		typ = gofire.TPtr{ETyp: typ}
+	}
+	for _, method := range pckg.methods[id.Name] {
+		if !ast.IsExported(method) {
+			continue
+		}
+		function := fmt.Sprintf("%s.%s", id.Name, method)
+		recv := gofire.Group{Name: rname(pckg.fdecls[function].decl, id.Name), Type: typ}
+		cmd, err := pckg.build(function, &recv)
+		if err != nil {
+			log.Printf("method %s is skipped, %v", function, err)
+			continue
 		}
+		chain = append(chain, *cmd)
+	}
+	return chain
+}
+
+// rname returns method receiver name or falls back to the lowercased receiver type name.
+func rname(fdecl *ast.FuncDecl, typ string) string {
+	if names := fdecl.Recv.List[0].Names; len(names) == 1 && names[0].Name != "_" {
+		return names[0].Name
+	}
+	return strings.ToLower(typ)
+}
+
+// receiver returns method receiver base type name if it's a simple named type.
+func receiver(fdecl *ast.FuncDecl) (string, bool) {
+	if fdecl.Recv == nil || len(fdecl.Recv.List) != 1 {
+		return "", false
+	}
+	tp := fdecl.Recv.List[0].Type
+	if star, ok := tp.(*ast.StarExpr); ok {
+		tp = star.X
 	}
-	if fparse == nil {
-		return nil, fmt.Errorf("function %s can't be found in ast package %s", function, pckg)
+	id, ok := tp.(*ast.Ident)
+	if !ok {
+		return "", false
 	}
-	return fparse(ctx)
+	return id.Name, true
 }
 
 type file struct {
@@ -109,7 +383,24 @@ func (f file) definition(pos, end token.Pos) string {
 	return f.buf.String()[fpos.Offset:fend.Offset]
 }
 
-type parser map[string]gofire.Group
+type tdecl struct {
+	file  file
+	gdecl *ast.GenDecl
+	tspec *ast.TypeSpec
+}
+
+type parser struct {
+	pckg      string
+	path      string
+	groups    map[string]gofire.Group
+	failed    map[string]error
+	types     map[string]tdecl
+	funcs     map[string]*ast.FuncDecl
+	consts    map[string][]string
+	resolving map[string]bool
+	importer  importer
+	info      *types.Info
+}
 
 func (p parser) results(f file, fdecl *ast.FuncDecl) (results []string) {
 	var list []*ast.Field
@@ -130,7 +421,18 @@ func (p parser) results(f file, fdecl *ast.FuncDecl) (results []string) {
 }
 
 func (p *parser) parameters(f file, fdecl *ast.FuncDecl) (parameters []gofire.Parameter, context bool, err error) {
+	dirs, err := directives(fdecl.Doc)
+	if err != nil {
+		return
+	}
+	// Inline signature comments take precedence over the function doc parameters section.
+	_, docs := section(strings.TrimSpace(fdecl.Doc.Text()))
+	for name, doc := range inline(f, fdecl) {
+		docs[name] = doc
+	}
 	var arg uint64
+	// optional holds the name of the first optional argument.
+	var optional string
 	var list []*ast.Field
 	if fdecl.Type.Params != nil {
 		list = fdecl.Type.Params.List
@@ -143,20 +445,45 @@ func (p *parser) parameters(f file, fdecl *ast.FuncDecl) (parameters []gofire.Pa
 		}
 		n := len(param.Names)
 		// Try to parse parameter as one of flag groups first.
-		g, ok := p.group(param.Type)
+		// Slices of flag groups are parsed as repeatable groups.
+		gtyp := param.Type
+		var repeatable bool
+		if at, ok := gtyp.(*ast.ArrayType); ok && at.Len == nil {
+			gtyp = at.Elt
+			repeatable = true
+		}
+		// Broken groups can't fallback to types, so report their own errors directly.
+		g, ok, gerr := p.group(f, gtyp)
+		if ok && gerr != nil {
+			err = fmt.Errorf(
+				"parameter %s type can't be parsed, %w",
+				f.definition(param.Pos(), param.End()),
+				gerr,
+			)
+			return
+		}
 		if ok {
+			g.Repeatable = repeatable
+			ptyp := g.Type
+			if repeatable {
+				ptyp = gofire.TSlice{ETyp: g.Type}
+			}
 			// Check if we need just a type placeholder instead of rich parameter.
 			if n == 0 {
-				parameters = append(parameters, gofire.Placeholder{Type: g.Type})
+				parameters = append(parameters, gofire.Placeholder{Type: ptyp})
 				continue
 			}
 			for i := 0; i < n; i++ {
 				name := param.Names[i].Name
 				// Check if we need just a type placeholder instead of rich parameter.
 				if name == "_" {
-					parameters = append(parameters, gofire.Placeholder{Type: g.Type})
+					parameters = append(parameters, gofire.Placeholder{Type: ptyp})
 					continue
 				}
+				if d, ok := dirs[name]; ok {
+					err = fmt.Errorf("%s directive is not supported for group parameter %s", d.kind, name)
+					return
+				}
 				group := *g
 				group.Name = param.Names[i].Name
 				parameters = append(parameters, group)
@@ -175,6 +502,10 @@ func (p *parser) parameters(f file, fdecl *ast.FuncDecl) (parameters []gofire.Pa
 			}
 		}
 		typ, terr := p.typ(ptyp)
+		// Variadic named types can't be converted from the parsed slice.
+		if terr == nil && ellipsis {
+			terr = unnamed(typ)
+		}
 		if terr != nil {
 			err = fmt.Errorf(
 				"parameter %s type can't be parsed, %w",
@@ -195,33 +526,120 @@ func (p *parser) parameters(f file, fdecl *ast.FuncDecl) (parameters []gofire.Pa
 				parameters = append(parameters, gofire.Placeholder{Type: typ})
 				continue
 			}
+			d, directed := dirs[name]
+			delete(dirs, name)
 			// In case type of parameter is pointer we define it as autoflag.
 			if ptr, ok := typ.(gofire.TPtr); ok {
-				parameters = append(parameters, gofire.Flag{
+				flag := &gofire.Flag{
 					Full:    name,
+					Doc:     docs[name],
 					Default: ptr.ETyp.Kind().Default(),
 					Type:    typ,
-				})
+				}
+				if directed {
+					if flag, err = p.dflag(name, typ, d); err != nil {
+						return
+					}
+					if flag.Doc == "" {
+						flag.Doc = docs[name]
+					}
+				}
+				parameters = append(parameters, *flag)
 				continue
 			}
 			// Otherwise parameter is positional argument.
-			parameters = append(parameters, gofire.Argument{
+			if directed && d.kind != "arg" {
+				err = fmt.Errorf("%s directive is not supported for argument parameter %s", d.kind, name)
+				return
+			}
+			// Argument is named after the parameter unless the directive overrides it.
+			argument := gofire.Argument{
 				Index:    uint64(arg),
 				Ellipsis: ellipsis,
+				Name:     name,
+				Doc:      docs[name],
 				Type:     typ,
-			})
+			}
+			if d.name != "" {
+				argument.Name = d.name
+			}
+			if d.doc != "" {
+				argument.Doc = d.doc
+			}
+			// Only trailing arguments could be optional, otherwise the arguments positions are ambiguous.
+			if d.dset {
+				if ellipsis {
+					err = fmt.Errorf("argument %s default is not supported for ellipsis argument", name)
+					return
+				}
+				v, _, perr := ParseTypeValue(typ, d.dval)
+				if perr != nil {
+					err = fmt.Errorf("argument %s default %s can't be parsed, %w", name, d.dval, perr)
+					return
+				}
+				argument.Optional, argument.Default = true, v
+				optional = name
+			} else if optional != "" && !ellipsis {
+				err = fmt.Errorf("argument %s is required, but it follows optional argument %s", name, optional)
+				return
+			}
+			parameters = append(parameters, argument)
 			arg++
 		}
 	}
+	// Directives for not existing parameters are most likely typos, so they are not ignored.
+	for name, d := range dirs {
+		err = fmt.Errorf("%s directive parameter %s can't be found", d.kind, name)
+		return
+	}
 	return
 }
 
+func (p parser) receiver(f file, fdecl *ast.FuncDecl) (*gofire.Group, error) {
+	typ, _ := receiver(fdecl)
+	g, ok := p.groups[typ]
+	if !ok {
+		return nil, fmt.Errorf(
+			"receiver %s type can't be parsed as flags group",
+			f.definition(fdecl.Recv.Pos(), fdecl.Recv.End()),
+		)
+	}
+	// Use receiver name as the group name or fallback to the type name.
+	g.Name = rname(fdecl, typ)
+	g.Persistent = true
+	return &g, nil
+}
+
+// register tries to build flags group from the struct type declaration and caches the result,
+// it's safe to register the same type multiple times as it's resolved lazily by other groups.
 func (p *parser) register(f file, gendecl *ast.GenDecl, tspec *ast.TypeSpec) error {
 	// In case it's not a structure type skip it.
 	stype, ok := tspec.Type.(*ast.StructType)
 	if !ok {
 		return nil
 	}
+	name := tspec.Name.Name
+	if _, ok := p.groups[name]; ok {
+		return nil
+	}
+	if err, ok := p.failed[name]; ok {
+		return err
+	}
+	if p.resolving[name] {
+		return fmt.Errorf("unsupported recursive type %s", name)
+	}
+	p.resolving[name] = true
+	defer delete(p.resolving, name)
+	g, err := p.build(f, gendecl, tspec, stype)
+	if err != nil {
+		p.failed[name] = err
+		return err
+	}
+	p.groups[name] = *g
+	return nil
+}
+
+func (p *parser) build(f file, gendecl *ast.GenDecl, tspec *ast.TypeSpec, stype *ast.StructType) (*gofire.Group, error) {
 	var g gofire.Group
 	g.Name = tspec.Name.Name
 	if tspec.Doc != nil {
@@ -229,59 +647,240 @@ func (p *parser) register(f file, gendecl *ast.GenDecl, tspec *ast.TypeSpec) err
 	} else {
 		g.Doc = strings.TrimSpace(gendecl.Doc.Text())
 	}
-	g.Type = gofire.TStruct{Typ: g.Name}
+	g.Type = gofire.TStruct{Typ: p.qualify(g.Name), Import: p.path}
 	for _, field := range stype.Fields.List {
-		// In case no flag names provided we can skip the embedded field.
+		var tag string
+		if field.Tag != nil {
+			tag = field.Tag.Value
+		}
+		// Embedded structs are flattened into the group or nested under their own prefix.
 		if len(field.Names) == 0 {
+			flags, err := p.embedded(f, field.Type, tag)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"embedded field %s can't be parsed, %w",
+					f.definition(field.Pos(), field.End()),
+					err,
+				)
+			}
+			// In case embedded field is not a struct we can skip it.
+			g.Flags = append(g.Flags, flags...)
 			continue
 		}
-		typ, err := p.typ(field.Type)
+		// Fields with custom parser are parsed as a single value, even if they are structs.
+		if fn := tagparser(tag); fn != "" {
+			typ, err := p.parser(f, field.Type, fn)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"field %s parser can't be used, %w",
+					f.definition(field.Pos(), field.End()),
+					err,
+				)
+			}
+			if err := p.field(f, &g, field, typ, tag); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Nested structs fields produce flags under the dotted field path.
+		ng, ok, err := p.group(f, field.Type)
 		if err != nil {
-			return fmt.Errorf(
-				"field %s type can't be parsed, %w",
+			return nil, fmt.Errorf(
+				"nested field %s can't be parsed, %w",
 				f.definition(field.Pos(), field.End()),
 				err,
 			)
 		}
-		var tag string
-		if field.Tag != nil {
-			tag = field.Tag.Value
+		if ok {
+			for _, name := range field.Names {
+				if name.Name == "_" {
+					continue
+				}
+				for _, flag := range ng.Flags {
+					// Short names can't be prefixed, so nested flags are only available by their full names.
+					flag.Full, flag.Short = fmt.Sprintf("%s.%s", name.Name, flag.Full), ""
+					if flag.Implies != "" {
+						flag.Implies = fmt.Sprintf("%s.%s", name.Name, flag.Implies)
+					}
+					g.Flags = append(g.Flags, flag)
+				}
+			}
+			continue
 		}
-		flag, set, err := p.tagflag(typ, tag)
+		typ, err := p.typ(field.Type)
 		if err != nil {
-			return fmt.Errorf(
-				"field %s tag can't be parsed, %w",
+			return nil, fmt.Errorf(
+				"field %s type can't be parsed, %w",
 				f.definition(field.Pos(), field.End()),
 				err,
 			)
 		}
-		// Short flag names supported only for single name structure fields.
-		if flag.Short != "" && len(field.Names) > 1 {
-			return fmt.Errorf(
-				"ambiguous short flag name %s for multiple fields %s",
-				flag.Short,
-				f.definition(field.Pos(), field.End()),
-			)
+		if err := p.field(f, &g, field, typ, tag); err != nil {
+			return nil, err
 		}
-		flag.Doc = strings.TrimSpace(field.Doc.Text())
-		flag.Type = typ
-		for _, name := range field.Names {
-			if name.Name == "_" {
-				continue
-			}
-			flag.Full = name.Name
-			// Fix broken default in case the flag wasn't set.
-			if !set {
-				flag.Default = flag.Type.Kind().Default()
+	}
+	// Promoted flags names could conflict with each other or with the group own flags.
+	fulls := make(map[string]bool, len(g.Flags))
+	for _, flag := range g.Flags {
+		if fulls[flag.Full] {
+			return nil, fmt.Errorf("conflicting flag name %s in group %s promoted fields", flag.Full, g.Name)
+		}
+		fulls[flag.Full] = true
+	}
+	// Implied flags are referenced by their fields names, so they have to be declared in the same group.
+	for _, flag := range g.Flags {
+		switch {
+		case flag.Implies == "":
+		case flag.Implies == flag.Full:
+			return nil, fmt.Errorf("flag %s can't imply itself in group %s", flag.Full, g.Name)
+		case !fulls[flag.Implies]:
+			return nil, fmt.Errorf("flag %s implies unknown flag %s in group %s", flag.Full, flag.Implies, g.Name)
+		}
+	}
+	return &g, nil
+}
+
+// field builds flags from the struct field with the provided type and appends them to the group.
+func (p *parser) field(f file, g *gofire.Group, field *ast.Field, typ gofire.Typ, tag string) error {
+	flag, opts, err := p.tagflag(typ, tag)
+	if err != nil {
+		return fmt.Errorf(
+			"field %s tag can't be parsed, %w",
+			f.definition(field.Pos(), field.End()),
+			err,
+		)
+	}
+	// Short flag names supported only for single name structure fields.
+	if flag.Short != "" && len(field.Names) > 1 {
+		return fmt.Errorf(
+			"ambiguous short flag name %s for multiple fields %s",
+			flag.Short,
+			f.definition(field.Pos(), field.End()),
+		)
+	}
+	flag.Doc = strings.TrimSpace(field.Doc.Text())
+	for _, name := range field.Names {
+		if name.Name == "_" {
+			continue
+		}
+		flag.Full = name.Name
+		// Fix broken default in case the flag wasn't set,
+		// pointer flags default to their element type default.
+		if !opts.set {
+			typ := flag.Type
+			if ptr, ok := typ.(gofire.TPtr); ok {
+				typ = ptr.ETyp
 			}
-			g.Flags = append(g.Flags, *flag)
+			flag.Default = typ.Kind().Default()
 		}
+		g.Flags = append(g.Flags, *flag)
 	}
-	(*p)[g.Type.Type()] = g
 	return nil
 }
 
+// embedded tries to build flags from the embedded struct field type, flags are promoted
+// into the parent group unless the field is tagged as nested. In case the field type
+// is not a struct no flags are returned.
+func (p *parser) embedded(f file, tp ast.Expr, tag string) ([]gofire.Flag, error) {
+	// Embedded pointers can't be safely assigned, so they are skipped along with other types.
+	var name string
+	switch t := tp.(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		name = t.Sel.Name
+	default:
+		return nil, nil
+	}
+	g, ok, err := p.group(f, tp)
+	if err != nil || !ok {
+		return nil, err
+	}
+	_, opts, err := p.tagflag(g.Type, tag)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]gofire.Flag, 0, len(g.Flags))
+	for _, flag := range g.Flags {
+		if opts.nested {
+			// Short names can't be prefixed, so nested flags are only available by their full names.
+			flag.Full, flag.Short = fmt.Sprintf("%s.%s", name, flag.Full), ""
+			if flag.Implies != "" {
+				flag.Implies = fmt.Sprintf("%s.%s", name, flag.Implies)
+			}
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// constants collects typed constants names from the constants declaration by their type names,
+// constants without explicit type repeat the previous type only if their values are omitted as well e.g. for iota.
+// Only iota driven constants declarations enumerate their types, other typed constants e.g. defaults are skipped.
+func (p *parser) constants(gdecl *ast.GenDecl) {
+	if !enumerates(gdecl) {
+		return
+	}
+	var ctyp string
+	for _, spec := range gdecl.Specs {
+		vspec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		switch {
+		case vspec.Type != nil:
+			ctyp = ""
+			if id, ok := vspec.Type.(*ast.Ident); ok {
+				ctyp = id.Name
+			}
+		case len(vspec.Values) > 0:
+			ctyp = ""
+		}
+		if ctyp == "" {
+			continue
+		}
+		for _, name := range vspec.Names {
+			// Constants declared in imported packages could be referenced only if they are exported.
+			if name.Name == "_" || (p.path != "" && !name.IsExported()) {
+				continue
+			}
+			p.consts[ctyp] = append(p.consts[ctyp], p.qualify(name.Name))
+		}
+	}
+}
+
+// enumerates checks whether any constant value in the constants declaration uses iota.
+func enumerates(gdecl *ast.GenDecl) bool {
+	var found bool
+	ast.Inspect(gdecl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// lgroup lazily resolves flags group for the struct type declared in the package.
+func (p *parser) lgroup(name string) (*gofire.Group, bool, error) {
+	tdecl, ok := p.types[name]
+	if !ok {
+		return nil, false, nil
+	}
+	if _, ok := tdecl.tspec.Type.(*ast.StructType); !ok {
+		return nil, false, nil
+	}
+	if err := p.register(tdecl.file, tdecl.gdecl, tdecl.tspec); err != nil {
+		return nil, true, err
+	}
+	g := p.groups[name]
+	return &g, true, nil
+}
+
 func (p parser) context(tp ast.Expr) bool {
+	if t, ok := p.checked(tp); ok {
+		return p.tcontext(t)
+	}
 	sel, ok := tp.(*ast.SelectorExpr)
 	if ok && sel.Sel.Name == "Context" {
 		ctx, ok := sel.X.(*ast.Ident)
@@ -290,16 +889,102 @@ func (p parser) context(tp ast.Expr) bool {
 	return false
 }
 
-func (p parser) group(tp ast.Expr) (*gofire.Group, bool) {
-	g, ok := tp.(*ast.Ident)
-	if ok {
-		g, ok := p[g.Name]
-		return &g, ok
+// group lazily resolves flags group for the type expression, it reports whether
+// the expression is a flags group at all and the group error if it can't be built.
+func (p *parser) group(f file, tp ast.Expr) (*gofire.Group, bool, error) {
+	if t, ok := p.checked(tp); ok {
+		return p.tgroup(t)
+	}
+	switch t := tp.(type) {
+	case *ast.Ident:
+		return p.lgroup(t.Name)
+	case *ast.SelectorExpr:
+		g, ok := p.imported(f, t)
+		return g, ok, nil
+	}
+	return nil, false, nil
+}
+
+// imported tries to build flags group from struct type declared in imported package.
+func (p parser) imported(f file, sel *ast.SelectorExpr) (*gofire.Group, bool) {
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || !ast.IsExported(sel.Sel.Name) {
+		return nil, false
+	}
+	for _, spec := range f.ast.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		// Skip all imports that obviously can't be referenced by the selector.
+		if (spec.Name != nil && spec.Name.Name != x.Name) || (spec.Name == nil && !strings.Contains(path, x.Name)) {
+			continue
+		}
+		pckg, err := p.importer.load(path)
+		if err != nil {
+			log.Print(err)
+			return nil, false
+		}
+		if spec.Name == nil && pckg.name != x.Name {
+			continue
+		}
+		return p.foreign(path, sel.Sel.Name)
 	}
 	return nil, false
 }
 
+// foreign tries to build flags group from struct type declared in package by provided import path.
+func (p parser) foreign(path, name string) (*gofire.Group, bool) {
+	pckg, err := p.importer.load(path)
+	if err != nil {
+		log.Print(err)
+		return nil, false
+	}
+	g, ok := pckg.parser.groups[name]
+	if !ok {
+		return nil, false
+	}
+	// Only exported fields could be set from another package.
+	flags := make([]gofire.Flag, 0, len(g.Flags))
+	for _, flag := range g.Flags {
+		if exported(flag.Full) {
+			flags = append(flags, flag)
+		}
+	}
+	g.Flags = flags
+	return &g, true
+}
+
+// exported checks that every segment of the flag path is exported.
+func exported(path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		if !ast.IsExported(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// tpath returns package path used for type checking.
+func (p parser) tpath() string {
+	if p.path == "" {
+		return p.pckg
+	}
+	return p.path
+}
+
+// qualify qualifies type name declared in imported package with the package name.
+func (p parser) qualify(name string) string {
+	if p.path == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", p.pckg, name)
+}
+
 func (p parser) typ(tp ast.Expr) (gofire.Typ, error) {
+	if t, ok := p.checked(tp); ok {
+		return p.ttyp(t)
+	}
 	switch tt := tp.(type) {
 	case *ast.Ident:
 		var k gofire.Kind
@@ -337,7 +1022,7 @@ func (p parser) typ(tp ast.Expr) (gofire.Typ, error) {
 		case gofire.String.Type():
 			k = gofire.String
 		default:
-			return nil, fmt.Errorf("unsupported primitive type %s", k.Type())
+			return p.named(tt)
 		}
 		return gofire.TPrimitive{TKind: k}, nil
 	case *ast.ArrayType:
@@ -345,6 +1030,9 @@ func (p parser) typ(tp ast.Expr) (gofire.Typ, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := unnamed(etyp); err != nil {
+			return nil, err
+		}
 		if tt.Len == nil {
 			return gofire.TSlice{ETyp: etyp}, nil
 		}
@@ -366,6 +1054,12 @@ func (p parser) typ(tp ast.Expr) (gofire.Typ, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := unnamed(ktyp); err != nil {
+			return nil, err
+		}
+		if err := unnamed(vtyp); err != nil {
+			return nil, err
+		}
 		return gofire.TMap{KTyp: ktyp, VTyp: vtyp}, nil
 	case *ast.StarExpr:
 		etyp, err := p.typ(tt.X)
@@ -373,17 +1067,95 @@ func (p parser) typ(tp ast.Expr) (gofire.Typ, error) {
 			return nil, err
 		}
 		return gofire.TPtr{ETyp: etyp}, nil
+	case *ast.SelectorExpr:
+		// Only time types are supported from imported packages.
+		if x, ok := tt.X.(*ast.Ident); ok && x.Name == "time" {
+			switch tt.Sel.Name {
+			case "Duration":
+				return gofire.TDuration{}, nil
+			case "Time":
+				return gofire.TTime{}, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported complex type")
 	default:
 		return nil, fmt.Errorf("unsupported complex type")
 	}
 }
 
-func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, bool, error) {
-	var f gofire.Flag
-	var set bool
+// named resolves named type or type alias declared in the package down to its underlying type.
+// Aliases are resolved to the aliased type itself, while named types keep their name for conversions.
+func (p parser) named(id *ast.Ident) (gofire.Typ, error) {
+	tdecl, ok := p.types[id.Name]
+	tspec := tdecl.tspec
+	if !ok {
+		return nil, fmt.Errorf("unsupported primitive type %s", gofire.Invalid.Type())
+	}
+	// Struct types are not resolved as they could be used only as flag groups.
+	if _, ok := tspec.Type.(*ast.StructType); ok {
+		return nil, fmt.Errorf("unsupported primitive type %s", gofire.Invalid.Type())
+	}
+	if p.resolving[id.Name] {
+		return nil, fmt.Errorf("unsupported recursive type %s", id.Name)
+	}
+	p.resolving[id.Name] = true
+	defer delete(p.resolving, id.Name)
+	typ, err := p.typ(tspec.Type)
+	if err != nil {
+		return nil, err
+	}
+	if tspec.Assign.IsValid() {
+		return typ, nil
+	}
+	// Named type defined from another named type shares the same underlying type.
+	if tn, ok := typ.(gofire.TNamed); ok {
+		typ = tn.ETyp
+	}
+	// Named string and integer types with declared constants are parsed by the constants names.
+	if consts := p.consts[id.Name]; len(consts) > 0 && enumerable(typ) {
+		return gofire.TText{Typ: p.qualify(id.Name), Import: p.path, Consts: consts, ETyp: typ}, nil
+	}
+	return gofire.TNamed{Typ: p.qualify(id.Name), ETyp: typ, Import: p.path}, nil
+}
+
+// enumerable checks whether the type values could be enumerated, only string and integer types are.
+func enumerable(typ gofire.Typ) bool {
+	switch typ.Kind() {
+	case gofire.String:
+	case gofire.Int, gofire.Int8, gofire.Int16, gofire.Int32, gofire.Int64:
+	case gofire.Uint, gofire.Uint8, gofire.Uint16, gofire.Uint32, gofire.Uint64:
+	default:
+		return false
+	}
+	return true
+}
+
+// unnamed checks that composite type element is not a named type,
+// as composite types can't be converted to named elements types.
+func unnamed(typ gofire.Typ) error {
+	switch t := typ.(type) {
+	case gofire.TNamed:
+		return fmt.Errorf("unsupported named composite element type %s", t.Typ)
+	case gofire.TText:
+		return fmt.Errorf("unsupported named composite element type %s", t.Typ)
+	}
+	return nil
+}
+
+// tagopts holds the tag options that are not part of the flag itself.
+type tagopts struct {
+	// set reports whether the flag default was set explicitly.
+	set bool
+	// nested reports whether the embedded struct flags should be kept under their own prefix.
+	nested bool
+}
+
+func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, tagopts, error) {
+	f := gofire.Flag{Type: typ}
+	var opts tagopts
 	// Skip empty tags they will be transformed into auto flags.
 	if rawTag == "" {
-		return &f, false, nil
+		return &f, opts, nil
 	}
 	rawTag = strings.Trim(rawTag, "`")
 	tags := splitb(rawTag, " ", `"`)
@@ -392,20 +1164,23 @@ func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, bool, erro
 		if len(parts) != 2 || parts[0] != "gofire" {
 			continue
 		}
-		tags := splitb(strings.Trim(parts[1], `"`), ",", "{", "}")
+		tags := joinq(splitb(strings.Trim(parts[1], `"`), ",", "{", "}"), ",")
 		// Skip omitted tags they will be transformed into auto flags.
 		if len(tags) == 1 && strings.TrimSpace(tags[0]) == "-" {
-			return &f, false, nil
+			return &f, opts, nil
 		}
+		// Default, enum and constraints values are parsed after all other tags as they depend on the type layout.
+		var dtag, dval, etag, eval string
+		var ctags []string
 		for _, tag := range tags {
 			tv := strings.SplitN(tag, "=", 2)
 			// Validate key/values and parse the value.
 			var val interface{}
 			tkn := strings.TrimSpace(tv[0])
 			switch tkn {
-			case "short", "default":
+			case "short", "default", "layout", "parser", "env", "enum", "min", "max", "pattern", "minlen", "maxlen", "xor", "with", "implies":
 				if len(tv) != 2 {
-					return nil, false, fmt.Errorf(
+					return nil, opts, fmt.Errorf(
 						"can't parse tag %s missing %q key value in %s",
 						tag,
 						tv[0],
@@ -417,18 +1192,39 @@ func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, bool, erro
 				if tkn == "short" {
 					for _, r := range v {
 						if !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
-							return nil, false, fmt.Errorf("can't parse tag %s short name %s is not alphanumeric", tag, tv[1])
+							return nil, opts, fmt.Errorf("can't parse tag %s short name %s is not alphanumeric", tag, tv[1])
+						}
+					}
+				}
+				if tkn == "env" {
+					for _, r := range v {
+						if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+							return nil, opts, fmt.Errorf("can't parse tag %s env name %s is not alphanumeric", tag, tv[1])
+						}
+					}
+				}
+				if tkn == "xor" || tkn == "with" {
+					for _, r := range v {
+						if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-') {
+							return nil, opts, fmt.Errorf("can't parse tag %s group name %s is not alphanumeric", tag, tv[1])
+						}
+					}
+				}
+				if tkn == "implies" {
+					for _, r := range v {
+						if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.') {
+							return nil, opts, fmt.Errorf("can't parse tag %s field name %s is not alphanumeric", tag, tv[1])
 						}
 					}
 				}
 				val = strings.ReplaceAll(v, `'`, `"`)
-			case "deprecated", "hidden":
+			case "deprecated", "hidden", "required", "optional", "nested":
 				if len(tv) == 1 {
 					val = true
 				} else {
 					valb, err := strconv.ParseBool(strings.TrimSpace(tv[1]))
 					if err != nil {
-						return nil, false, fmt.Errorf(
+						return nil, opts, fmt.Errorf(
 							"can't parse tag %s as boolean for %q key and %s value in %s",
 							tag,
 							tv[0],
@@ -439,7 +1235,7 @@ func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, bool, erro
 					val = valb
 				}
 			default:
-				return nil, false, fmt.Errorf(
+				return nil, opts, fmt.Errorf(
 					"can't parse tag %s unsupported %q key in %s",
 					tag,
 					tv[0],
@@ -451,24 +1247,288 @@ func (p parser) tagflag(typ gofire.Typ, rawTag string) (*gofire.Flag, bool, erro
 			case "short":
 				f.Short = val.(string)
 			case "default":
-				v, pset, err := ParseTypeValue(typ, val.(string))
-				if err != nil {
-					return nil, false, fmt.Errorf(
-						"can't parse tag %s value %v in %s",
+				dtag, dval = tag, val.(string)
+			case "enum":
+				etag, eval = tag, val.(string)
+			case "min", "max", "pattern", "minlen", "maxlen":
+				ctags = append(ctags, tag)
+			case "layout":
+				ltyp, ok := layout(f.Type, unquote(val.(string)))
+				if !ok {
+					return nil, opts, fmt.Errorf(
+						"can't parse tag %s layout is not supported for type %s in %s",
+						tag,
+						f.Type.Type(),
+						rawTag,
+					)
+				}
+				f.Type = ltyp
+			case "parser":
+				// Parser function is resolved before the tag along with the type, see tagparser.
+				if t, ok := f.Type.(gofire.TText); !ok || t.Parser == "" {
+					return nil, opts, fmt.Errorf(
+						"can't parse tag %s parser is not supported for type %s in %s",
 						tag,
-						err,
+						f.Type.Type(),
 						rawTag,
 					)
 				}
-				set = pset
-				f.Default = v
+			case "env":
+				f.Env = val.(string)
+			case "xor":
+				f.Xor = val.(string)
+			case "with":
+				f.With = val.(string)
+			case "implies":
+				f.Implies = val.(string)
 			case "deprecated":
 				f.Deprecated = val.(bool)
 			case "hidden":
 				f.Hidden = val.(bool)
+			case "required":
+				f.Required = val.(bool)
+			case "optional":
+				if _, ok := f.Type.(gofire.TPtr); !ok && val.(bool) {
+					return nil, opts, fmt.Errorf(
+						"can't parse tag %s requires a pointer field, got type %s in %s",
+						tag,
+						f.Type.Type(),
+						rawTag,
+					)
+				}
+				f.Optional = val.(bool)
+			case "nested":
+				opts.nested = val.(bool)
 			}
 		}
-		return &f, set, nil
+		if etag != "" {
+			enum, err := enum(f.Type, eval)
+			if err != nil {
+				return nil, opts, fmt.Errorf(
+					"can't parse tag %s %v in %s",
+					etag,
+					err,
+					rawTag,
+				)
+			}
+			f.Enum = enum
+		}
+		for _, ctag := range ctags {
+			if err := constrain(&f, ctag); err != nil {
+				return nil, opts, fmt.Errorf(
+					"can't parse tag %s %v in %s",
+					ctag,
+					err,
+					rawTag,
+				)
+			}
+		}
+		if dtag != "" {
+			v, pset, err := ParseTypeValue(f.Type, dval)
+			if err != nil {
+				return nil, opts, fmt.Errorf(
+					"can't parse tag %s value %v in %s",
+					dtag,
+					err,
+					rawTag,
+				)
+			}
+			if choices := f.Choices(); pset && len(choices) > 0 && !contains(choices, fmt.Sprint(v)) {
+				return nil, opts, fmt.Errorf(
+					"can't parse tag %s value %v is not one of %s in %s",
+					dtag,
+					v,
+					strings.Join(choices, ", "),
+					rawTag,
+				)
+			}
+			if err := satisfy(f, v); pset && err != nil {
+				return nil, opts, fmt.Errorf(
+					"can't parse tag %s value %v in %s",
+					dtag,
+					err,
+					rawTag,
+				)
+			}
+			opts.set = pset
+			f.Default = v
+		}
+		return &f, opts, nil
+	}
+	return &f, opts, nil
+}
+
+// enum parses the enum tag literal e.g. {debug,info,warn} accordingly to the type,
+// the values are kept in their canonical text form so they could be compared to the flag values.
+func enum(typ gofire.Typ, literal string) ([]string, error) {
+	etyp := elem(typ)
+	if !enumerable(etyp) {
+		return nil, fmt.Errorf("enum is not supported for type %s", typ.Type())
+	}
+	if !strings.HasPrefix(literal, "{") || !strings.HasSuffix(literal, "}") {
+		return nil, fmt.Errorf("enum values %s are not enclosed in braces", literal)
+	}
+	var values []string
+	for _, item := range splitb(literal[1:len(literal)-1], ",", `"`) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		v, _, err := ParseTypeValue(etyp, item)
+		if err != nil {
+			return nil, fmt.Errorf("enum value %v", err)
+		}
+		if value := fmt.Sprint(v); !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("enum values %s are empty", literal)
+	}
+	return values, nil
+}
+
+// elem unwraps the pointer and the named type to the type of the flag values.
+func elem(typ gofire.Typ) gofire.Typ {
+	if t, ok := typ.(gofire.TPtr); ok {
+		typ = t.ETyp
 	}
-	return &f, false, nil
+	if t, ok := typ.(gofire.TNamed); ok {
+		typ = t.ETyp
+	}
+	return typ
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// tagparser returns the custom parser function name from the tag if it's provided,
+// as the parser function defines the flag type it's resolved before the rest of the tag.
+func tagparser(rawTag string) string {
+	rawTag = strings.Trim(rawTag, "`")
+	for _, ftag := range splitb(rawTag, " ", `"`) {
+		parts := strings.SplitN(ftag, ":", 2)
+		if len(parts) != 2 || parts[0] != "gofire" {
+			continue
+		}
+		for _, tag := range splitb(strings.Trim(parts[1], `"`), ",", "{", "}") {
+			tv := strings.SplitN(tag, "=", 2)
+			if len(tv) == 2 && strings.TrimSpace(tv[0]) == "parser" {
+				return strings.TrimSpace(tv[1])
+			}
+		}
+	}
+	return ""
+}
+
+// parser resolves the field type that is parsed by the custom parser function, the function
+// has to be declared in the package with func(string) (T, error) signature where T is the field type.
+func (p parser) parser(f file, tp ast.Expr, fn string) (gofire.Typ, error) {
+	fd, ok := p.funcs[fn]
+	if !ok {
+		return nil, fmt.Errorf("parser function %s is not declared", fn)
+	}
+	// Parser function from another package could be called only if it's exported.
+	if p.path != "" && !ast.IsExported(fn) {
+		return nil, fmt.Errorf("parser function %s is not exported", fn)
+	}
+	params, results := fields(fd.Type.Params), fields(fd.Type.Results)
+	if len(params) != 1 ||
+		len(results) != 2 ||
+		!p.identical(params[0], ast.NewIdent("string")) ||
+		!p.identical(results[0], tp) ||
+		!p.identical(results[1], ast.NewIdent("error")) {
+		return nil, fmt.Errorf(
+			"parser function %s signature doesn't match func(string) (%s, error)",
+			fn,
+			types.ExprString(tp),
+		)
+	}
+	name, path, ok := p.pname(f, tp)
+	if !ok {
+		return nil, fmt.Errorf("unsupported parser type %s", types.ExprString(tp))
+	}
+	return gofire.TText{Typ: name, Import: path, Parser: p.qualify(fn)}, nil
+}
+
+// pname returns qualified type name and its import path for the type parsed by the custom parser.
+func (p parser) pname(f file, tp ast.Expr) (string, string, bool) {
+	switch t := tp.(type) {
+	case *ast.Ident:
+		// Predeclared types don't need to be qualified unless they are shadowed.
+		if _, ok := p.types[t.Name]; !ok && types.Universe.Lookup(t.Name) != nil {
+			return t.Name, "", true
+		}
+		return p.qualify(t.Name), p.path, true
+	case *ast.StarExpr:
+		name, path, ok := p.pname(f, t.X)
+		return "*" + name, path, ok
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", "", false
+		}
+		for _, spec := range f.ast.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			if name == x.Name {
+				return types.ExprString(t), path, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// identical checks whether both type expressions denote the same type,
+// type checked types are compared precisely otherwise types are compared literally.
+func (p parser) identical(x, y ast.Expr) bool {
+	tx, okx := p.checked(x)
+	ty, oky := p.checked(y)
+	if okx && oky {
+		return types.Identical(tx, ty)
+	}
+	return types.ExprString(x) == types.ExprString(y)
+}
+
+// fields flattens the fields list into the list of types, one per every field name.
+func fields(fl *ast.FieldList) []ast.Expr {
+	if fl == nil {
+		return nil
+	}
+	var list []ast.Expr
+	for _, field := range fl.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			list = append(list, field.Type)
+		}
+	}
+	return list
+}
+
+// layout sets the time layout for time types or pointers to time types.
+func layout(typ gofire.Typ, l string) (gofire.Typ, bool) {
+	switch t := typ.(type) {
+	case gofire.TTime:
+		return gofire.TTime{Layout: l}, true
+	case gofire.TPtr:
+		if _, ok := t.ETyp.(gofire.TTime); ok {
+			return gofire.TPtr{ETyp: gofire.TTime{Layout: l}}, true
+		}
+	}
+	return nil, false
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/1pkg/gofire"
 )
@@ -29,6 +30,19 @@ func (fsm mapfs) ReadDir(name string) ([]fs.DirEntry, error) {
 	return dir, fsm.dirErr
 }
 
+type importfs struct {
+	fstest.MapFS
+	packages map[string]fstest.MapFS
+}
+
+func (fsi importfs) Import(ctx context.Context, path string) (fs.FS, string, error) {
+	pckg, ok := fsi.packages[path]
+	if !ok {
+		return nil, "", fmt.Errorf("package %s can't be imported", path)
+	}
+	return importfs{MapFS: pckg, packages: fsi.packages}, path[strings.LastIndex(path, "/")+1:], nil
+}
+
 func TestParse(t *testing.T) {
 	escape := func(body string) []byte {
 		return []byte(strings.ReplaceAll(body, "#", "`"))
@@ -166,7 +180,7 @@ func TestParse(t *testing.T) {
 				Doc:        "bar function doc.",
 				Context:    true,
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
 					gofire.Placeholder{Type: gofire.TPrimitive{TKind: gofire.Uint}},
 					gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Flag{Full: "c", Default: float64(0.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float32}}},
@@ -200,12 +214,12 @@ func TestParse(t *testing.T) {
 				Doc:        "bar function doc.",
 				Context:    true,
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
 					gofire.Placeholder{Type: gofire.TPrimitive{TKind: gofire.Uint}},
 					gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Flag{Full: "c", Default: float64(0.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float32}}},
 					gofire.Flag{Full: "d", Default: float64(0.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float64}}},
-					gofire.Argument{Index: 1, Ellipsis: true, Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Argument{Index: 1, Ellipsis: true, Name: "f", Type: gofire.TPrimitive{TKind: gofire.Int}},
 				},
 				Results: []string{"int"},
 			},
@@ -235,7 +249,7 @@ func TestParse(t *testing.T) {
 				Doc:        "bar function doc.",
 				Context:    true,
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
 					gofire.Placeholder{Type: gofire.TPrimitive{TKind: gofire.Uint}},
 					gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Flag{Full: "c", Default: float64(0.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float32}}},
@@ -270,7 +284,7 @@ func TestParse(t *testing.T) {
 				Doc:        "bar function doc.",
 				Context:    true,
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
 					gofire.Placeholder{Type: gofire.TPrimitive{TKind: gofire.Uint}},
 					gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Flag{Full: "c", Default: float64(0.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float32}}},
@@ -311,17 +325,17 @@ func TestParse(t *testing.T) {
 					Data: escape(`
 						package foo
 
-						import "regexp"
+						import "bytes"
 
 						// bar function doc.
-						func bar(*regexp.Regexp) {
+						func bar(*bytes.Buffer) {
 						}
 					`),
 				},
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter *regexp.Regexp type can't be parsed, unsupported complex type"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter *bytes.Buffer type can't be parsed, unsupported complex type"),
 		},
 		"valid go package with valid function definition and group reference should produce expected command": {
 			ctx: context.TODO(),
@@ -404,13 +418,13 @@ func TestParse(t *testing.T) {
 				Function:   "bar",
 				Definition: "func bar(a int8, b []string, cz z, _ z)",
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
-					gofire.Argument{Index: 1, Type: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 1, Name: "b", Type: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Group{
 						Name: "cz",
 						Doc:  "z a flag group",
 						Flags: []gofire.Flag{
-							{Full: "a", Default: nil, Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+							{Full: "a", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 							{Full: "b", Doc: "b flag boolean", Default: false, Type: gofire.TPrimitive{TKind: gofire.Bool}},
 							{Full: "complex", Default: map[interface{}]interface{}{}, Type: gofire.TMap{KTyp: gofire.TPrimitive{TKind: gofire.String}, VTyp: gofire.TPrimitive{TKind: gofire.Int}}},
 						},
@@ -452,7 +466,7 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter cz z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter cz z type can't be parsed, field iface zi type can't be parsed, unsupported complex type"),
 		},
 		"valid go package with valid function definition and group reference with tags should produce expected command": {
 			ctx: context.TODO(),
@@ -486,8 +500,8 @@ func TestParse(t *testing.T) {
 				Function:   "bar",
 				Definition: "func bar(a int8, b []string, cz z) (r1, r2, r3 int)",
 				Parameters: []gofire.Parameter{
-					gofire.Argument{Index: 0, Type: gofire.TPrimitive{TKind: gofire.Int8}},
-					gofire.Argument{Index: 1, Type: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
+					gofire.Argument{Index: 1, Name: "b", Type: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
 					gofire.Group{
 						Name: "cz",
 						Flags: []gofire.Flag{
@@ -495,7 +509,7 @@ func TestParse(t *testing.T) {
 							{Full: "b", Deprecated: true, Default: "str", Type: gofire.TPrimitive{TKind: gofire.String}},
 							{Full: "long", Short: "l", Hidden: true, Default: complex128(0.0), Type: gofire.TPrimitive{TKind: gofire.Complex128}},
 							{Full: "c", Default: complex128(0.0), Type: gofire.TPrimitive{TKind: gofire.Complex64}},
-							{Full: "d", Default: nil, Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Uint8}}},
+							{Full: "d", Default: uint64(0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Uint8}}},
 						},
 						Type: gofire.TStruct{Typ: "z"},
 					},
@@ -564,7 +578,7 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, field bad string `gofire:\"short=b=a=d\"` tag can't be parsed, can't parse tag short=b=a=d short name b=a=d is not alphanumeric"),
 		},
 		"valid go package with valid function definition and group reference with invalid tags should produce expected error": {
 			ctx: context.TODO(),
@@ -589,7 +603,7 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, field a string `gofire:\"tag=true\"` tag can't be parsed, can't parse tag tag=true unsupported \"tag\" key in gofire:\"tag=true\""),
 		},
 		"valid go package with valid function definition and group reference with ambiguous short tags should produce expected error": {
 			ctx: context.TODO(),
@@ -614,7 +628,7 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, ambiguous short flag name c for multiple fields a, b string `gofire:\"short=c\"`"),
 		},
 		"valid go package with valid function definition and group reference with invalid string tags should produce expected error": {
 			ctx: context.TODO(),
@@ -639,7 +653,7 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, field a string `gofire:\"default\"` tag can't be parsed, can't parse tag default missing \"default\" key value in gofire:\"default\""),
 		},
 		"valid go package with valid function definition and group reference with invalid bool tags should produce expected error": {
 			ctx: context.TODO(),
@@ -664,17 +678,1694 @@ func TestParse(t *testing.T) {
 			},
 			pckg:     "foo",
 			function: "bar",
-			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, unsupported primitive type invalid"),
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter az z type can't be parsed, field a string `gofire:\"hidden=10\"` tag can't be parsed, can't parse tag hidden=10 as boolean for \"hidden\" key and 10 value in gofire:\"hidden=10\""),
 		},
-	}
-	for tname, tcase := range table {
-		t.Run(tname, func(t *testing.T) {
-			cmd, err := Parse(tcase.ctx, tcase.dir, tcase.pckg, tcase.function)
-			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
-				t.Fatalf("expected error message %q but got %q", tcase.err, err)
-			}
-			if !reflect.DeepEqual(tcase.cmd, cmd) {
-				t.Fatalf("expected cmd %#v but got %#v", tcase.cmd, cmd)
+		"valid named types and aliases should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// bar function doc.
+						func bar(p Port, l Labels, h *Host, az z) {
+						}
+					`),
+				},
+				"types.go": {
+					Data: escape(`
+						package foo
+
+						type Port Number
+
+						type Number uint16
+
+						type Labels = map[string]string
+
+						type Host string
+
+						type z struct {
+							timeout Seconds #gofire:"default=10"#
+						}
+
+						type Seconds = Timeout
+
+						type Timeout int
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(p Port, l Labels, h *Host, az z)",
+				Doc:        "bar function doc.",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "p", Type: gofire.TNamed{Typ: "Port", ETyp: gofire.TPrimitive{TKind: gofire.Uint16}}},
+					gofire.Argument{Index: 1, Name: "l", Type: gofire.TMap{KTyp: gofire.TPrimitive{TKind: gofire.String}, VTyp: gofire.TPrimitive{TKind: gofire.String}}},
+					gofire.Flag{Full: "h", Default: "", Type: gofire.TPtr{ETyp: gofire.TNamed{Typ: "Host", ETyp: gofire.TPrimitive{TKind: gofire.String}}}},
+					gofire.Group{
+						Name: "az",
+						Flags: []gofire.Flag{
+							{Full: "timeout", Default: int64(10), Type: gofire.TNamed{Typ: "Timeout", ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+						},
+						Type: gofire.TStruct{Typ: "z"},
+					},
+				},
+			},
+		},
+		"named composite element type should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type Port int
+
+						func bar(ps []Port) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter ps []Port type can't be parsed, unsupported named composite element type Port"),
+		},
+		"variadic named type should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type Port int
+
+						func bar(ps ...Port) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter ps ...Port type can't be parsed, unsupported named composite element type Port"),
+		},
+		"recursive named type should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type List []List
+
+						func bar(l List) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter l List type can't be parsed, unsupported recursive type List"),
+		},
+		"valid group with embedded structs should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// serve options.
+						type serve struct {
+							common
+							tls #gofire:"nested"#
+							*ignored
+							// serve port.
+							Port int #gofire:"short=p,default=8080"#
+						}
+
+						type common struct {
+							// verbose output.
+							Verbose bool
+						}
+
+						type tls struct {
+							Cert, Key string
+						}
+
+						type ignored struct {
+							Skip bool
+						}
+
+						func bar(s serve) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(s serve)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "s",
+						Doc:  "serve options.",
+						Flags: []gofire.Flag{
+							{Full: "Verbose", Default: false, Doc: "verbose output.", Type: gofire.TPrimitive{TKind: gofire.Bool}},
+							{Full: "tls.Cert", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "tls.Key", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "Port", Short: "p", Default: int64(8080), Doc: "serve port.", Type: gofire.TPrimitive{TKind: gofire.Int}},
+						},
+						Type: gofire.TStruct{Typ: "serve"},
+					},
+				},
+			},
+		},
+		"group with conflicting embedded structs fields should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type serve struct {
+							common
+							Verbose bool
+						}
+
+						type common struct {
+							Verbose bool
+						}
+
+						func bar(s serve) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter s serve type can't be parsed, conflicting flag name Verbose in group serve promoted fields"),
+		},
+		"valid group with nested structs should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// config options.
+						type config struct {
+							db, cache store
+							// config name.
+							name string
+						}
+
+						type store struct {
+							host string #gofire:"default=localhost"#
+						}
+
+						func bar(cfg config) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(cfg config)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "cfg",
+						Doc:  "config options.",
+						Flags: []gofire.Flag{
+							{Full: "db.host", Default: "localhost", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "cache.host", Default: "localhost", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "name", Default: "", Doc: "config name.", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Type: gofire.TStruct{Typ: "config"},
+					},
+				},
+			},
+		},
+		"valid group with nested structs short tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type config struct {
+							db, cache store
+						}
+
+						type store struct {
+							host string #gofire:"short=H"#
+						}
+
+						func bar(cfg config) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(cfg config)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "cfg",
+						Flags: []gofire.Flag{
+							{Full: "db.host", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "cache.host", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Type: gofire.TStruct{Typ: "config"},
+					},
+				},
+			},
+		},
+		"valid repeatable group should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type server struct {
+							host string
+						}
+
+						func bar(servers []server, _ []server) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(servers []server, _ []server)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "servers",
+						Flags: []gofire.Flag{
+							{Full: "host", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Type:       gofire.TStruct{Typ: "server"},
+						Repeatable: true,
+					},
+					gofire.Placeholder{Type: gofire.TSlice{ETyp: gofire.TStruct{Typ: "server"}}},
+				},
+			},
+		},
+		"valid imported group should produce expected command": {
+			ctx: context.TODO(),
+			dir: importfs{
+				MapFS: fstest.MapFS{
+					"file.go": {
+						Data: escape(`
+							package foo
+
+							import (
+								"example.com/x/dbopts"
+								o "example.com/x/opts"
+							)
+
+							func bar(db dbopts.Options, _ o.Options) {
+							}
+						`),
+					},
+				},
+				packages: map[string]fstest.MapFS{
+					"example.com/x/dbopts": {
+						"options.go": {
+							Data: escape(`
+								package dbopts
+
+								// Options database options.
+								type Options struct {
+									// database host.
+									Host string #gofire:"default=localhost"#
+									Port Port
+									secret string
+								}
+
+								type Port int
+							`),
+						},
+					},
+					"example.com/x/opts": {
+						"options.go": {
+							Data: escape(`
+								package opts
+
+								type Options struct {
+									Verbose bool
+								}
+							`),
+						},
+					},
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(db dbopts.Options, _ o.Options)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "db",
+						Doc:  "Options database options.",
+						Flags: []gofire.Flag{
+							{Full: "Host", Default: "localhost", Doc: "database host.", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "Port", Default: int64(0), Type: gofire.TNamed{Typ: "dbopts.Port", ETyp: gofire.TPrimitive{TKind: gofire.Int}, Import: "example.com/x/dbopts"}},
+						},
+						Type: gofire.TStruct{Typ: "dbopts.Options", Import: "example.com/x/dbopts"},
+					},
+					gofire.Placeholder{Type: gofire.TStruct{Typ: "opts.Options", Import: "example.com/x/opts"}},
+				},
+			},
+		},
+		"missing imported group should produce expected error message": {
+			ctx: context.TODO(),
+			dir: importfs{
+				MapFS: fstest.MapFS{
+					"file.go": {
+						Data: escape(`
+							package foo
+
+							import "example.com/x/dbopts"
+
+							func bar(db dbopts.Options) {
+							}
+						`),
+					},
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter db dbopts.Options type can't be parsed, unsupported complex type"),
+		},
+		"type checked package should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						import (
+							stdctx "context"
+							"time"
+						)
+
+						const size = 2 * 2
+
+						// bar function doc.
+						func bar(ctx stdctx.Context, a [size]int, d *time.Duration, b byte) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(ctx stdctx.Context, a [size]int, d *time.Duration, b byte)",
+				Doc:        "bar function doc.",
+				Context:    true,
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TArray{ETyp: gofire.TPrimitive{TKind: gofire.Int}, Size: 4}},
+					gofire.Flag{Full: "d", Default: time.Duration(0), Type: gofire.TPtr{ETyp: gofire.TDuration{}}},
+					gofire.Argument{Index: 1, Name: "b", Type: gofire.TPrimitive{TKind: gofire.Uint8}},
+				},
+			},
+		},
+		"time types with layout tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						import "time"
+
+						type retry struct {
+							interval time.Duration #gofire:"default=30s"#
+							until    time.Time     #gofire:"layout=2006-01-02,default=2021-01-02"#
+						}
+
+						func bar(r retry, since time.Time) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(r retry, since time.Time)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "r",
+						Flags: []gofire.Flag{
+							{Full: "interval", Default: 30 * time.Second, Type: gofire.TDuration{}},
+							{Full: "until", Default: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), Type: gofire.TTime{Layout: "2006-01-02"}},
+						},
+						Type: gofire.TStruct{Typ: "retry"},
+					},
+					gofire.Argument{Index: 0, Name: "since", Type: gofire.TTime{}},
+				},
+			},
+		},
+		"layout tag on non time type should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type retry struct {
+							interval int #gofire:"layout=2006-01-02"#
+						}
+
+						func bar(r retry) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter r retry type can't be parsed, field interval int `gofire:\"layout=2006-01-02\"` tag can't be parsed, can't parse tag layout=2006-01-02 layout is not supported for type int in gofire:\"layout=2006-01-02\""),
+		},
+		"types with text form should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						import (
+							"net"
+							"strings"
+						)
+
+						type id struct {
+							v string
+						}
+
+						func (i *id) UnmarshalText(text []byte) error {
+							i.v = string(text)
+							return nil
+						}
+
+						type tags []string
+
+						func (t tags) String() string {
+							return strings.Join(t, ",")
+						}
+
+						func (t *tags) Set(v string) error {
+							*t = append(*t, v)
+							return nil
+						}
+
+						type filter struct {
+							tags tags
+							ip   net.IP #gofire:"default=127.0.0.1"#
+						}
+
+						func bar(i id, f filter, ip *net.IP) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(i id, f filter, ip *net.IP)",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "i", Type: gofire.TText{Typ: "id"}},
+					gofire.Group{
+						Name: "f",
+						Flags: []gofire.Flag{
+							{Full: "tags", Default: "", Type: gofire.TText{Typ: "tags", Value: true}},
+							{Full: "ip", Default: "127.0.0.1", Type: gofire.TText{Typ: "net.IP", Import: "net"}},
+						},
+						Type: gofire.TStruct{Typ: "filter"},
+					},
+					gofire.Flag{Full: "ip", Default: "", Type: gofire.TPtr{ETyp: gofire.TText{Typ: "net.IP", Import: "net"}}},
+				},
+			},
+		},
+		"types with custom parsers should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						import (
+							"net/url"
+							"strings"
+						)
+
+						type region struct {
+							name string
+							zone int
+						}
+
+						func parseRegion(s string) (region, error) {
+							return region{name: strings.ToLower(s)}, nil
+						}
+
+						func parseURL(s string) (*url.URL, error) {
+							return url.Parse(s)
+						}
+
+						type opts struct {
+							region   region   #gofire:"parser=parseRegion,default=eu"#
+							endpoint *url.URL #gofire:"parser=parseURL"#
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(o opts)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "o",
+						Flags: []gofire.Flag{
+							{Full: "region", Default: "eu", Type: gofire.TText{Typ: "region", Parser: "parseRegion"}},
+							{Full: "endpoint", Default: "", Type: gofire.TText{Typ: "*url.URL", Import: "net/url", Parser: "parseURL"}},
+						},
+						Type: gofire.TStruct{Typ: "opts"},
+					},
+				},
+			},
+		},
+		"types with mismatched custom parsers should produce expected error message": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type region struct {
+							name string
+						}
+
+						func parseRegion(s string) region {
+							return region{name: s}
+						}
+
+						type opts struct {
+							region region #gofire:"parser=parseRegion"#
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter o opts type can't be parsed, field region region `gofire:\"parser=parseRegion\"` parser can't be used, parser function parseRegion signature doesn't match func(string) (region, error)"),
+		},
+		"group with env tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type db struct {
+							host string #gofire:"env=DB_HOST,default=localhost"#
+							port int    #gofire:"short=p,env=DB_PORT"#
+						}
+
+						func bar(d db) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(d db)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "d",
+						Flags: []gofire.Flag{
+							{Full: "host", Env: "DB_HOST", Default: "localhost", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "port", Short: "p", Env: "DB_PORT", Default: int64(0), Type: gofire.TPrimitive{TKind: gofire.Int}},
+						},
+						Type: gofire.TStruct{Typ: "db"},
+					},
+				},
+			},
+		},
+		"group with required tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type db struct {
+							host string #gofire:"required"#
+							port int    #gofire:"required=false,default=5432"#
+						}
+
+						func bar(d db) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(d db)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "d",
+						Flags: []gofire.Flag{
+							{Full: "host", Required: true, Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "port", Default: int64(5432), Type: gofire.TPrimitive{TKind: gofire.Int}},
+						},
+						Type: gofire.TStruct{Typ: "db"},
+					},
+				},
+			},
+		},
+		"group with enum tags and constants should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type level int
+
+						const (
+							debug level = iota
+							info
+							warn
+						)
+
+						type opts struct {
+							format string #gofire:"enum={text, json},default=text"#
+							retry  int    #gofire:"enum={1,2,3}"#
+							lvl    level  #gofire:"default=info"#
+						}
+
+						func bar(o opts, l level) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(o opts, l level)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "o",
+						Flags: []gofire.Flag{
+							{Full: "format", Enum: []string{"text", "json"}, Default: "text", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "retry", Enum: []string{"1", "2", "3"}, Default: int64(0), Type: gofire.TPrimitive{TKind: gofire.Int}},
+							{Full: "lvl", Default: "info", Type: gofire.TText{Typ: "level", Consts: []string{"debug", "info", "warn"}, ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+						},
+						Type: gofire.TStruct{Typ: "opts"},
+					},
+					gofire.Argument{Index: 0, Name: "l", Type: gofire.TText{Typ: "level", Consts: []string{"debug", "info", "warn"}, ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+				},
+			},
+		},
+		"named type with non iota typed constants should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type port int
+
+						const defaultPort port = 8080
+
+						func bar(p port) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(p port)",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "p", Type: gofire.TNamed{Typ: "port", ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+				},
+			},
+		},
+		"group with enum default out of enum values should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type opts struct {
+							format string #gofire:"enum={text,json},default=xml"#
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter o opts type can't be parsed, field format string `gofire:\"enum={text,json},default=xml\"` tag can't be parsed, can't parse tag default=xml value xml is not one of text, json in gofire:\"enum={text,json},default=xml\""),
+		},
+		"group with constraint tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type server struct {
+							host  string   #gofire:"pattern='^[a-z]{1,16}(\.[a-z]+)*$',default=localhost"#
+							port  uint16   #gofire:"min=1,max=65535,default=8080"#
+							load  float64  #gofire:"min=0.5"#
+							name  string   #gofire:"minlen=2,maxlen=8"#
+							tags  []string #gofire:"maxlen=3"#
+						}
+
+						func bar(s server) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(s server)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "s",
+						Flags: []gofire.Flag{
+							{Full: "host", Pattern: `^[a-z]{1,16}(\.[a-z]+)*$`, Default: "localhost", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "port", Min: "1", Max: "65535", Default: uint64(8080), Type: gofire.TPrimitive{TKind: gofire.Uint16}},
+							{Full: "load", Min: "0.5", Default: float64(0), Type: gofire.TPrimitive{TKind: gofire.Float64}},
+							{Full: "name", MinLen: "2", MaxLen: "8", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "tags", MaxLen: "3", Default: []interface{}{}, Type: gofire.TSlice{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+						},
+						Type: gofire.TStruct{Typ: "server"},
+					},
+				},
+			},
+		},
+		"group with default out of constraint range should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type server struct {
+							port int #gofire:"min=1,max=65535,default=0"#
+						}
+
+						func bar(s server) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter s server type can't be parsed, field port int `gofire:\"min=1,max=65535,default=0\"` tag can't be parsed, can't parse tag default=0 value 0 is less than min 1 in gofire:\"min=1,max=65535,default=0\""),
+		},
+		"group with xor and with tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type opts struct {
+							file string #gofire:"xor=source"#
+							url  string #gofire:"xor=source"#
+							cert string #gofire:"with=tls-pair"#
+							key  string #gofire:"with=tls-pair"#
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(o opts)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "o",
+						Flags: []gofire.Flag{
+							{Full: "file", Xor: "source", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "url", Xor: "source", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "cert", With: "tls-pair", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "key", With: "tls-pair", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Type: gofire.TStruct{Typ: "opts"},
+					},
+				},
+			},
+		},
+		"group with implies tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type tls struct {
+							cert string #gofire:"implies=key"#
+							key  string
+						}
+
+						type opts struct {
+							sec tls
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(o opts)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "o",
+						Flags: []gofire.Flag{
+							{Full: "sec.cert", Implies: "sec.key", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+							{Full: "sec.key", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Type: gofire.TStruct{Typ: "opts"},
+					},
+				},
+			},
+		},
+		"group with implies tag on unknown field should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type opts struct {
+							cert string #gofire:"implies=secret"#
+							key  string
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter o opts type can't be parsed, flag cert implies unknown flag secret in group opts"),
+		},
+		"group with optional tags should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type opts struct {
+							timeout *int    #gofire:"optional,default=30"#
+							retries *uint8  #gofire:"optional"#
+							name    *string
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(o opts)",
+				Parameters: []gofire.Parameter{
+					gofire.Group{
+						Name: "o",
+						Flags: []gofire.Flag{
+							{Full: "timeout", Optional: true, Default: int64(30), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+							{Full: "retries", Optional: true, Default: uint64(0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Uint8}}},
+							{Full: "name", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+						},
+						Type: gofire.TStruct{Typ: "opts"},
+					},
+				},
+			},
+		},
+		"group with optional tag on non pointer field should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						type opts struct {
+							timeout int #gofire:"optional"#
+						}
+
+						func bar(o opts) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter o opts type can't be parsed, field timeout int `gofire:\"optional\"` tag can't be parsed, can't parse tag optional requires a pointer field, got type int in gofire:\"optional\""),
+		},
+		"function with param and arg directives should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// bar function doc.
+						//gofire:param c short=c default=2.0 doc='result multiplier'
+						//gofire:param l name=log-level enum={debug, info} default=info deprecated
+						//gofire:arg a name=left doc='left operand'
+						func bar(a int, b int, c *float64, l *string) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(a int, b int, c *float64, l *string)",
+				Doc:        "bar function doc.",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "left", Doc: "left operand", Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Argument{Index: 1, Name: "b", Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Flag{Full: "c", Short: "c", Doc: "result multiplier", Default: float64(2.0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Float64}}},
+					gofire.Flag{Full: "log-level", Enum: []string{"debug", "info"}, Deprecated: true, Default: "info", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+				},
+			},
+		},
+		"function with parameters doc section and inline comments should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// bar function doc.
+						//
+						// Parameters:
+						//
+						//	a: the left operand.
+						//	b - the right
+						//	  operand.
+						//	c: the multiplier.
+						//
+						// bar function notes.
+						func bar(a int, b int /* the second operand. */, c *int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(a int, b int /* the second operand. */, c *int)",
+				Doc:        "bar function doc.\n\nbar function notes.",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "a", Doc: "the left operand.", Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Argument{Index: 1, Name: "b", Doc: "the second operand.", Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Flag{Full: "c", Doc: "the multiplier.", Default: int64(0), Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.Int}}},
+				},
+			},
+		},
+		"function with invalid param directive should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:param c min=10 default=2
+						func bar(c *int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, parameter c directive can't be parsed, can't parse tag default=2 value 2 is less than min 10 in gofire:\"min=10,default=2\""),
+		},
+		"function with param directive for argument should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:param a short=a
+						func bar(a int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, param directive is not supported for argument parameter a"),
+		},
+		"function with directive for unknown parameter should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:arg b name=right
+						func bar(a int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, arg directive parameter b can't be found"),
+		},
+		"function with command directives should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// syncUsers synchronizes users.
+						//
+						//gofire:command name=sync-users aliases=su,sync hidden
+						//gofire:command long='synchronizes users between the databases.'
+						//gofire:command example='sync-users --force' example='su'
+						func syncUsers() {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "syncUsers",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "syncUsers",
+				Definition: "func syncUsers()",
+				Doc:        "syncUsers synchronizes users.",
+				Name:       "sync-users",
+				Aliases:    []string{"su", "sync"},
+				Long:       "synchronizes users between the databases.",
+				Examples:   []string{"sync-users --force", "su"},
+				Hidden:     true,
+			},
+		},
+		"function with silent command directive should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:command silent
+						func bar() int {
+							return 0
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar() int",
+				Silent:     true,
+				Results:    []string{"int"},
+			},
+		},
+		"function with invalid silent command directive should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:command silent=maybe
+						func bar() {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, can't parse directive //gofire:command silent=maybe as boolean for \"silent\" key and maybe value"),
+		},
+		"function with invalid command directive name should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:command name='sync users'
+						func bar() {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, can't parse directive //gofire:command name='sync users' command name sync users is not alphanumeric"),
+		},
+		"function with optional arg directive should produce expected command": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						// bar function doc.
+						//
+						//gofire:arg b default=10
+						func bar(a string, b int, c ...int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(a string, b int, c ...int)",
+				Doc:        "bar function doc.",
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.String}},
+					gofire.Argument{Index: 1, Name: "b", Optional: true, Default: int64(10), Type: gofire.TPrimitive{TKind: gofire.Int}},
+					gofire.Argument{Index: 2, Name: "c", Ellipsis: true, Type: gofire.TPrimitive{TKind: gofire.Int}},
+				},
+			},
+		},
+		"function with required arg after optional arg should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:arg a default=1
+						func bar(a int, b int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, argument b is required, but it follows optional argument a"),
+		},
+		"function with invalid optional arg default should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:arg a default=ten
+						func bar(a int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, argument a default ten can't be parsed, strconv.ParseInt: parsing \"ten\": invalid syntax"),
+		},
+		"function with ellipsis arg default should produce expected error": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						//gofire:arg a default=1
+						func bar(a ...int) {
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			err:      errors.New("ast file file.go in package foo function bar ast parsing error, argument a default is not supported for ellipsis argument"),
+		},
+		"not type checked package should fallback to ast parsing": {
+			ctx: context.TODO(),
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: escape(`
+						package foo
+
+						import "context"
+
+						// bar function doc.
+						func bar(ctx context.Context, a int) {
+							undefined(a)
+						}
+					`),
+				},
+			},
+			pckg:     "foo",
+			function: "bar",
+			cmd: &gofire.Command{
+				Package:    "foo",
+				Function:   "bar",
+				Definition: "func bar(ctx context.Context, a int)",
+				Doc:        "bar function doc.",
+				Context:    true,
+				Parameters: []gofire.Parameter{
+					gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int}},
+				},
+			},
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			cmd, err := Parse(tcase.ctx, tcase.dir, tcase.pckg, tcase.function)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if !reflect.DeepEqual(tcase.cmd, cmd) {
+				t.Fatalf("expected cmd %#v but got %#v", tcase.cmd, cmd)
+			}
+		})
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	dir := fstest.MapFS{
+		"file.go": {
+			Data: []byte(`
+				// Package foo doc.
+				package foo
+
+				// bar function doc.
+				func bar(a int8) int {
+					return 0
+				}
+
+				// baz function doc.
+				func baz(b *string) {
+				}
+
+				// bar method doc.
+				func (foo) bar() {
+				}
+
+				type foo struct{}
+
+				// client doc.
+				type client struct {
+					addr string
+				}
+
+				// Fetch method doc.
+				func (c *client) Fetch(id string) {
+				}
+
+				func (client) Ping() bool {
+					return true
+				}
+
+				// dial function doc.
+				func dial(addr string) *client {
+					return &client{addr: addr}
+				}
+
+				type num int
+
+				func (n num) Inc() {
+				}
+			`),
+		},
+	}
+	table := map[string]struct {
+		functions []string
+		tree      *gofire.Tree
+		err       error
+	}{
+		"no functions should produce expected error message": {
+			err: errors.New("no functions provided for ast package foo"),
+		},
+		"missing function should produce expected error message": {
+			functions: []string{"bar", "qux"},
+			err:       errors.New("function qux can't be found in ast package foo"),
+		},
+		"type without exported methods should produce expected error message": {
+			functions: []string{"bar", "foo"},
+			err:       errors.New("type foo has no exported methods in ast package foo"),
+		},
+		"method with unsupported receiver should produce expected error message": {
+			functions: []string{"num.Inc"},
+			err:       errors.New("ast file file.go in package foo method num.Inc ast parsing error, receiver (n num) type can't be parsed as flags group"),
+		},
+		"valid method should produce expected commands tree": {
+			functions: []string{"client.Fetch"},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "foo",
+				Doc:     "Package foo doc.",
+				Commands: []gofire.Command{
+					{
+						Package:  "foo",
+						Function: "Fetch",
+						Receiver: &gofire.Group{
+							Name:       "c",
+							Doc:        "client doc.",
+							Flags:      []gofire.Flag{{Full: "addr", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}}},
+							Type:       gofire.TStruct{Typ: "client"},
+							Persistent: true,
+						},
+						Definition: "func (c *client) Fetch(id string)",
+						Doc:        "Fetch method doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Argument{Index: 0, Name: "id", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+					},
+				},
+			},
+		},
+		"function with named result type should produce expected chained commands": {
+			functions: []string{"dial"},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "foo",
+				Doc:     "Package foo doc.",
+				Commands: []gofire.Command{
+					{
+						Package:    "foo",
+						Function:   "dial",
+						Definition: "func dial(addr string) *client",
+						Doc:        "dial function doc.",
+						Results:    []string{"*client"},
+						Parameters: []gofire.Parameter{
+							gofire.Argument{Index: 0, Name: "addr", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+						Chain: []gofire.Command{
+							{
+								Package:    "foo",
+								Function:   "Fetch",
+								Receiver:   &gofire.Group{Name: "c", Type: gofire.TPtr{ETyp: gofire.TStruct{Typ: "client"}}},
+								Definition: "func (c *client) Fetch(id string)",
+								Doc:        "Fetch method doc.",
+								Parameters: []gofire.Parameter{
+									gofire.Argument{Index: 0, Name: "id", Type: gofire.TPrimitive{TKind: gofire.String}},
+								},
+							},
+							{
+								Package:    "foo",
+								Function:   "Ping",
+								Receiver:   &gofire.Group{Name: "client", Type: gofire.TPtr{ETyp: gofire.TStruct{Typ: "client"}}},
+								Definition: "func (client) Ping() bool",
+								Results:    []string{"bool"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"valid type should produce expected commands tree": {
+			functions: []string{"client"},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "client",
+				Doc:     "client doc.",
+				Commands: []gofire.Command{
+					{
+						Package:  "foo",
+						Function: "Fetch",
+						Receiver: &gofire.Group{
+							Name:       "c",
+							Doc:        "client doc.",
+							Flags:      []gofire.Flag{{Full: "addr", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}}},
+							Type:       gofire.TStruct{Typ: "client"},
+							Persistent: true,
+						},
+						Definition: "func (c *client) Fetch(id string)",
+						Doc:        "Fetch method doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Argument{Index: 0, Name: "id", Type: gofire.TPrimitive{TKind: gofire.String}},
+						},
+					},
+					{
+						Package:  "foo",
+						Function: "Ping",
+						Receiver: &gofire.Group{
+							Name:       "client",
+							Doc:        "client doc.",
+							Flags:      []gofire.Flag{{Full: "addr", Default: "", Type: gofire.TPrimitive{TKind: gofire.String}}},
+							Type:       gofire.TStruct{Typ: "client"},
+							Persistent: true,
+						},
+						Definition: "func (client) Ping() bool",
+						Results:    []string{"bool"},
+					},
+				},
+			},
+		},
+		"duplicated functions should produce expected error message": {
+			functions: []string{"bar", "baz", "bar"},
+			err:       errors.New("function bar is duplicated in ast package foo"),
+		},
+		"valid functions should produce expected commands tree": {
+			functions: []string{"baz", "bar"},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "foo",
+				Doc:     "Package foo doc.",
+				Commands: []gofire.Command{
+					{
+						Package:    "foo",
+						Function:   "baz",
+						Definition: "func baz(b *string)",
+						Doc:        "baz function doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+						},
+					},
+					{
+						Package:    "foo",
+						Function:   "bar",
+						Definition: "func bar(a int8) int",
+						Doc:        "bar function doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
+						},
+						Results: []string{"int"},
+					},
+				},
+			},
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			tree, err := ParseTree(context.TODO(), dir, "foo", tcase.functions...)
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if !reflect.DeepEqual(tcase.tree, tree) {
+				t.Fatalf("expected tree %#v but got %#v", tcase.tree, tree)
+			}
+		})
+	}
+}
+
+func TestParsePackage(t *testing.T) {
+	table := map[string]struct {
+		dir  fs.FS
+		tree *gofire.Tree
+		err  error
+	}{
+		"package without exported functions should produce expected error message": {
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: []byte(`
+						package foo
+
+						func bar(a int8) {
+						}
+					`),
+				},
+			},
+			err: errors.New("no exported functions can be parsed in ast package foo"),
+		},
+		"package with generated file should produce expected package doc and commands tree": {
+			dir: fstest.MapFS{
+				"a.go": {
+					Data: []byte(`
+						// a file doc.
+						package foo
+
+						// Bar function doc.
+						func Bar() {
+						}
+					`),
+				},
+				"doc.go": {
+					Data: []byte(`
+						// Package foo doc.
+						package foo
+					`),
+				},
+				"flag.gen.go": {
+					Data: []byte(`// THIS IS AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+						package foo
+
+						// CommandBarFlag is autogenerated cli interface for Bar function.
+						func CommandBarFlag() {
+						}
+					`),
+				},
+			},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "foo",
+				Doc:     "Package foo doc.",
+				Commands: []gofire.Command{
+					{
+						Package:    "foo",
+						Function:   "Bar",
+						Definition: "func Bar()",
+						Doc:        "Bar function doc.",
+					},
+				},
+			},
+		},
+		"package with exported functions should produce expected commands tree": {
+			dir: fstest.MapFS{
+				"file.go": {
+					Data: []byte(`
+						package foo
+
+						import "bytes"
+
+						// Baz function doc.
+						func Baz(b *string) {
+						}
+
+						// Bar function doc.
+						func Bar(a int8) int {
+							return 0
+						}
+
+						func bar() {
+						}
+
+						// Buffer function isn't supported.
+						func Buffer(*bytes.Buffer) {
+						}
+
+						// Bar method doc.
+						func (Foo) Bar() {
+						}
+
+						type Foo struct{}
+					`),
+				},
+			},
+			tree: &gofire.Tree{
+				Package: "foo",
+				Name:    "foo",
+				Commands: []gofire.Command{
+					{
+						Package:    "foo",
+						Function:   "Baz",
+						Definition: "func Baz(b *string)",
+						Doc:        "Baz function doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Flag{Full: "b", Default: "", Type: gofire.TPtr{ETyp: gofire.TPrimitive{TKind: gofire.String}}},
+						},
+					},
+					{
+						Package:    "foo",
+						Function:   "Bar",
+						Definition: "func Bar(a int8) int",
+						Doc:        "Bar function doc.",
+						Parameters: []gofire.Parameter{
+							gofire.Argument{Index: 0, Name: "a", Type: gofire.TPrimitive{TKind: gofire.Int8}},
+						},
+						Results: []string{"int"},
+					},
+				},
+			},
+		},
+	}
+	for tname, tcase := range table {
+		t.Run(tname, func(t *testing.T) {
+			tree, err := ParsePackage(context.TODO(), tcase.dir, "foo")
+			if fmt.Sprintf("%v", tcase.err) != fmt.Sprintf("%v", err) {
+				t.Fatalf("expected error message %q but got %q", tcase.err, err)
+			}
+			if !reflect.DeepEqual(tcase.tree, tree) {
+				t.Fatalf("expected tree %#v but got %#v", tcase.tree, tree)
 			}
 		})
 	}
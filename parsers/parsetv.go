@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/1pkg/gofire"
@@ -11,8 +12,15 @@ import (
 
 // ParseTypeValue parses provided string value accordingly to the value type.
 func ParseTypeValue(t gofire.Typ, val string) (interface{}, bool, error) {
+	// Named types values are parsed accordingly to their underlying type.
+	if tn, ok := t.(gofire.TNamed); ok {
+		t = tn.ETyp
+	}
 	k := t.Kind()
 	switch k {
+	case gofire.Ptr:
+		// Pointer values are parsed accordingly to their element type.
+		return ParseTypeValue(t.(gofire.TPtr).ETyp, val)
 	case gofire.Array:
 		return parseTypeValueRange(t.(gofire.TArray).ETyp, int(t.(gofire.TArray).Size), val)
 	case gofire.Slice:
@@ -54,10 +62,38 @@ func ParseTypeValue(t gofire.Typ, val string) (interface{}, bool, error) {
 			return sval, true, nil
 		}
 		return val, true, nil
+	case gofire.Duration:
+		if val == "" {
+			return k.Default(), false, nil
+		}
+		v, err := time.ParseDuration(unquote(val))
+		return v, err == nil, err
+	case gofire.Time:
+		if val == "" {
+			return k.Default(), false, nil
+		}
+		layout := time.RFC3339
+		if tt, ok := t.(gofire.TTime); ok {
+			layout = tt.TimeLayout()
+		}
+		v, err := time.Parse(layout, unquote(val))
+		return v, err == nil, err
+	case gofire.Text:
+		// Text values are kept raw as they are parsed only by the type itself.
+		v := unquote(val)
+		return v, v != "", nil
 	}
 	return nil, false, nil
 }
 
+// unquote unquotes the value if it's quoted, otherwise the value is returned as is.
+func unquote(val string) string {
+	if uval, err := strconv.Unquote(val); err == nil {
+		return uval
+	}
+	return val
+}
+
 func parseTypeValueRange(t gofire.Typ, size int, val string) (interface{}, bool, error) {
 	v := strings.Map(func(r rune) rune {
 		if unicode.IsSpace(r) {
@@ -6,6 +6,7 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/1pkg/gofire"
 )
@@ -117,6 +118,44 @@ func TestParseTypeValue(t *testing.T) {
 			out: uint64(math.MaxUint32),
 			err: errors.New(`strconv.ParseUint: parsing "18446744073709551615": value out of range`),
 		},
+		"duration type value should be parsed as a duration value": {
+			typ: gofire.TDuration{},
+			val: "1m30s",
+			set: true,
+			out: 90 * time.Second,
+		},
+		"duration type empty value should be parsed as a duration value": {
+			typ: gofire.TDuration{},
+			out: time.Duration(0),
+		},
+		"duration type int value should fail on parse": {
+			typ: gofire.TDuration{},
+			val: "10",
+			out: time.Duration(0),
+			err: errors.New(`time: missing unit in duration "10"`),
+		},
+		"time type value should be parsed as a time value": {
+			typ: gofire.TTime{},
+			val: "2021-02-03T04:05:06Z",
+			set: true,
+			out: time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC),
+		},
+		"time type value with layout should be parsed as a time value": {
+			typ: gofire.TTime{Layout: "2006-01-02"},
+			val: `"2021-02-03"`,
+			set: true,
+			out: time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		"time type empty value should be parsed as a time value": {
+			typ: gofire.TTime{},
+			out: time.Time{},
+		},
+		"time type invalid value should fail on parse": {
+			typ: gofire.TTime{Layout: "2006-01-02"},
+			val: "value",
+			out: time.Time{},
+			err: errors.New(`parsing time "value" as "2006-01-02": cannot parse "value" as "2006"`),
+		},
 		"float32 type float value should be parsed as a float64 value": {
 			typ: gofire.TPrimitive{TKind: gofire.Float32},
 			val: "-12.125",
@@ -29,3 +29,16 @@ loop:
 	}
 	return result
 }
+
+// joinq joins back the tokens that were split inside of single quoted sequences.
+func joinq(tokens []string, by string) []string {
+	result := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if l := len(result); l > 0 && strings.Count(result[l-1], "'")%2 != 0 {
+			result[l-1] += by + t
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
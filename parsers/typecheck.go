@@ -0,0 +1,286 @@
+package parsers
+
+import (
+	"fmt"
+	"go/ast"
+	goimporter "go/importer"
+	"go/token"
+	"go/types"
+	"io/fs"
+
+	"github.com/1pkg/gofire"
+)
+
+// srcimporter defines go types importer that type checks imported packages from sources
+// relatively to the package source directory, imported packages are cached only by the importer itself,
+// so every loaded package gets fresh imported packages types.
+type srcimporter struct {
+	dir      string
+	importer types.ImporterFrom
+}
+
+func (i srcimporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, i.dir, 0)
+}
+
+func (i srcimporter) ImportFrom(path, _ string, mode types.ImportMode) (*types.Package, error) {
+	return i.importer.ImportFrom(path, i.dir, mode)
+}
+
+// check type checks all package files and returns collected types info.
+// In case type checking fails nil is returned, so pure ast parsing is used instead.
+func check(dir fs.FS, fset *token.FileSet, path string, files []file) *types.Info {
+	var src string
+	if d, ok := dir.(dirfs); ok {
+		src = d.dir
+	}
+	asts := make([]*ast.File, 0, len(files))
+	for _, f := range files {
+		asts = append(asts, f.ast)
+	}
+	info := types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf := types.Config{Importer: srcimporter{
+		dir:      src,
+		importer: goimporter.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}}
+	if _, err := conf.Check(path, fset, asts, &info); err != nil {
+		return nil
+	}
+	return &info
+}
+
+var tkinds = map[types.BasicKind]gofire.Kind{
+	types.Bool:       gofire.Bool,
+	types.Int:        gofire.Int,
+	types.Int8:       gofire.Int8,
+	types.Int16:      gofire.Int16,
+	types.Int32:      gofire.Int32,
+	types.Int64:      gofire.Int64,
+	types.Uint:       gofire.Uint,
+	types.Uint8:      gofire.Uint8,
+	types.Uint16:     gofire.Uint16,
+	types.Uint32:     gofire.Uint32,
+	types.Uint64:     gofire.Uint64,
+	types.Float32:    gofire.Float32,
+	types.Float64:    gofire.Float64,
+	types.Complex64:  gofire.Complex64,
+	types.Complex128: gofire.Complex128,
+	types.String:     gofire.String,
+}
+
+var (
+	// textUnmarshaler mirrors encoding.TextUnmarshaler interface.
+	textUnmarshaler = types.NewInterfaceType([]*types.Func{
+		types.NewFunc(token.NoPos, nil, "UnmarshalText", types.NewSignature(
+			nil,
+			types.NewTuple(types.NewVar(token.NoPos, nil, "text", types.NewSlice(types.Typ[types.Byte]))),
+			types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())),
+			false,
+		)),
+	}, nil).Complete()
+	// flagValue mirrors flag.Value interface.
+	flagValue = types.NewInterfaceType([]*types.Func{
+		types.NewFunc(token.NoPos, nil, "String", types.NewSignature(
+			nil,
+			nil,
+			types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String])),
+			false,
+		)),
+		types.NewFunc(token.NoPos, nil, "Set", types.NewSignature(
+			nil,
+			types.NewTuple(types.NewVar(token.NoPos, nil, "value", types.Typ[types.String])),
+			types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())),
+			false,
+		)),
+	}, nil).Complete()
+)
+
+// implements checks whether the type or the pointer to the type implements the interface.
+func implements(t types.Type, iface *types.Interface) bool {
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
+
+// ttext checks whether the named type could be parsed from its text form
+// through encoding.TextUnmarshaler or flag.Value interfaces.
+func (p parser) ttext(t *types.Named) (gofire.Typ, bool) {
+	obj := t.Obj()
+	if obj.Pkg() == nil {
+		return nil, false
+	}
+	var value bool
+	switch {
+	case implements(t, textUnmarshaler):
+	case implements(t, flagValue):
+		value = true
+	default:
+		return nil, false
+	}
+	name, path := p.qualify(obj.Name()), p.path
+	if obj.Pkg().Path() != p.tpath() {
+		name, path = fmt.Sprintf("%s.%s", obj.Pkg().Name(), obj.Name()), obj.Pkg().Path()
+	}
+	return gofire.TText{Typ: name, Import: path, Value: value}, true
+}
+
+// tconsts returns the qualified names of the iota driven constants of the named type in their declaration order,
+// the constants are collected from the type package declarations, as iota isn't preserved by the type checker.
+func (p parser) tconsts(t *types.Named, local bool) []string {
+	if local {
+		return p.consts[t.Obj().Name()]
+	}
+	pckg, err := p.importer.load(t.Obj().Pkg().Path())
+	if err != nil {
+		return nil
+	}
+	return pckg.parser.consts[t.Obj().Name()]
+}
+
+// checked returns type checked type of the expression if it's available.
+func (p parser) checked(tp ast.Expr) (types.Type, bool) {
+	if p.info == nil {
+		return nil, false
+	}
+	tv, ok := p.info.Types[tp]
+	if !ok || !tv.IsType() {
+		return nil, false
+	}
+	return tv.Type, true
+}
+
+// unalias resolves type aliases down to the actual types, alias types are checked
+// by interface to avoid depending on the newer go types api directly.
+func unalias(t types.Type) types.Type {
+	for {
+		alias, ok := t.(interface{ Rhs() types.Type })
+		if !ok {
+			return t
+		}
+		t = alias.Rhs()
+	}
+}
+
+func (p parser) ttyp(t types.Type) (gofire.Typ, error) {
+	switch tt := unalias(t).(type) {
+	case *types.Basic:
+		k, ok := tkinds[tt.Kind()]
+		if !ok {
+			return nil, fmt.Errorf("unsupported primitive type %s", gofire.Invalid.Type())
+		}
+		return gofire.TPrimitive{TKind: k}, nil
+	case *types.Named:
+		obj := tt.Obj()
+		// Time types are supported natively instead of their underlying types.
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" {
+			switch obj.Name() {
+			case "Duration":
+				return gofire.TDuration{}, nil
+			case "Time":
+				return gofire.TTime{}, nil
+			}
+		}
+		// Types with text form are parsed through their own interfaces.
+		if typ, ok := p.ttext(tt); ok {
+			return typ, nil
+		}
+		local := obj.Pkg() != nil && obj.Pkg().Path() == p.tpath()
+		if _, ok := tt.Underlying().(*types.Struct); ok {
+			// Struct types are not resolved as they could be used only as flag groups.
+			if local {
+				return nil, fmt.Errorf("unsupported primitive type %s", gofire.Invalid.Type())
+			}
+			return nil, fmt.Errorf("unsupported complex type")
+		}
+		if obj.Pkg() == nil {
+			return nil, fmt.Errorf("unsupported complex type")
+		}
+		name, path := p.qualify(obj.Name()), p.path
+		if !local {
+			name, path = fmt.Sprintf("%s.%s", obj.Pkg().Name(), obj.Name()), obj.Pkg().Path()
+		}
+		if p.resolving[name] {
+			return nil, fmt.Errorf("unsupported recursive type %s", obj.Name())
+		}
+		p.resolving[name] = true
+		defer delete(p.resolving, name)
+		typ, err := p.ttyp(tt.Underlying())
+		if err != nil {
+			return nil, err
+		}
+		// Named string and integer types with declared constants are parsed by the constants names.
+		if consts := p.tconsts(tt, local); len(consts) > 0 && enumerable(typ) {
+			return gofire.TText{Typ: name, Import: path, Consts: consts, ETyp: typ}, nil
+		}
+		return gofire.TNamed{Typ: name, ETyp: typ, Import: path}, nil
+	case *types.Pointer:
+		etyp, err := p.ttyp(tt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return gofire.TPtr{ETyp: etyp}, nil
+	case *types.Slice:
+		etyp, err := p.ttyp(tt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if err := unnamed(etyp); err != nil {
+			return nil, err
+		}
+		return gofire.TSlice{ETyp: etyp}, nil
+	case *types.Array:
+		etyp, err := p.ttyp(tt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if err := unnamed(etyp); err != nil {
+			return nil, err
+		}
+		return gofire.TArray{ETyp: etyp, Size: tt.Len()}, nil
+	case *types.Map:
+		ktyp, err := p.ttyp(tt.Key())
+		if err != nil {
+			return nil, err
+		}
+		vtyp, err := p.ttyp(tt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if err := unnamed(ktyp); err != nil {
+			return nil, err
+		}
+		if err := unnamed(vtyp); err != nil {
+			return nil, err
+		}
+		return gofire.TMap{KTyp: ktyp, VTyp: vtyp}, nil
+	default:
+		return nil, fmt.Errorf("unsupported complex type")
+	}
+}
+
+func (p parser) tcontext(t types.Type) bool {
+	named, ok := unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+func (p *parser) tgroup(t types.Type) (*gofire.Group, bool, error) {
+	named, ok := unalias(t).(*types.Named)
+	if !ok {
+		return nil, false, nil
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil, false, nil
+	}
+	// Struct types with text form are parsed as a single value instead of flags group.
+	if _, ok := p.ttext(named); ok {
+		return nil, false, nil
+	}
+	if obj.Pkg().Path() == p.tpath() {
+		return p.lgroup(obj.Name())
+	}
+	g, ok := p.foreign(obj.Pkg().Path(), obj.Name())
+	return g, ok, nil
+}
@@ -3,6 +3,7 @@ package gofire
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Kind holds information about supported data kinds.
@@ -31,6 +32,9 @@ const (
 	Map
 	Ptr
 	Struct
+	Duration
+	Time
+	Text
 	// Kinds bellow are not parsed and not processed by generators
 	// but still defined here for visibility and potentially could be
 	// processed in the future.
@@ -82,6 +86,12 @@ func (k Kind) Type() string {
 		return "map"
 	case Ptr:
 		return "ptr"
+	case Duration:
+		return "time.Duration"
+	case Time:
+		return "time.Time"
+	case Text:
+		return "text"
 	default:
 		return "invalid"
 	}
@@ -105,6 +115,12 @@ func (k Kind) Default() interface{} {
 		return []interface{}{}
 	case Map:
 		return map[interface{}]interface{}{}
+	case Duration:
+		return time.Duration(0)
+	case Time:
+		return time.Time{}
+	case Text:
+		return ""
 	default:
 		return nil
 	}
@@ -262,8 +278,103 @@ func (t TPtr) Format(v interface{}) string {
 	return "nil"
 }
 
+type TDuration struct{}
+
+func (TDuration) Kind() Kind {
+	return Duration
+}
+
+func (TDuration) Type() string {
+	return Duration.Type()
+}
+
+func (TDuration) Format(v interface{}) string {
+	return fmt.Sprintf("%s(%d)", Duration.Type(), v)
+}
+
+// TTime holds optional time layout that is used to parse the time values,
+// time.RFC3339 layout is used by default.
+type TTime struct {
+	Layout string
+}
+
+func (TTime) Kind() Kind {
+	return Time
+}
+
+func (TTime) Type() string {
+	return Time.Type()
+}
+
+func (t TTime) Format(v interface{}) string {
+	tv, ok := v.(time.Time)
+	if !ok || tv.IsZero() {
+		return fmt.Sprintf("%s{}", Time.Type())
+	}
+	tv = tv.UTC()
+	return fmt.Sprintf(
+		"time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)",
+		tv.Year(),
+		tv.Month(),
+		tv.Day(),
+		tv.Hour(),
+		tv.Minute(),
+		tv.Second(),
+		tv.Nanosecond(),
+	)
+}
+
+// TimeLayout returns the time layout or time.RFC3339 if the layout is not set.
+func (t TTime) TimeLayout() string {
+	if t.Layout == "" {
+		return time.RFC3339
+	}
+	return t.Layout
+}
+
+// TText represents a type that is parsed from its text form either through
+// encoding.TextUnmarshaler, through flag.Value if Value is set, through
+// the package level func(string) (T, error) Parser function if it is set
+// or by the names of the type package constants if Consts are set, in which case
+// ETyp holds the constants underlying type, so their literal values are accepted as well.
+// Its values are kept as raw text and are parsed only by the generated code.
+type TText struct {
+	Typ    string
+	Import string
+	Value  bool
+	Parser string
+	Consts []string
+	ETyp   Typ
+}
+
+func (TText) Kind() Kind {
+	return Text
+}
+
+func (t TText) Type() string {
+	return t.Typ
+}
+
+func (t TText) Format(v interface{}) string {
+	if v == nil {
+		v = ""
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+// Names returns the type constants names without their package qualifiers,
+// as they are provided in the command line.
+func (t TText) Names() []string {
+	names := make([]string, 0, len(t.Consts))
+	for _, c := range t.Consts {
+		names = append(names, c[strings.LastIndex(c, ".")+1:])
+	}
+	return names
+}
+
 type TStruct struct {
-	Typ string
+	Typ    string
+	Import string
 }
 
 func (TStruct) Kind() Kind {
@@ -277,3 +388,21 @@ func (t TStruct) Type() string {
 func (t TStruct) Format(v interface{}) string {
 	return fmt.Sprintf("%s{}", t.Typ)
 }
+
+type TNamed struct {
+	Typ    string
+	ETyp   Typ
+	Import string
+}
+
+func (t TNamed) Kind() Kind {
+	return t.ETyp.Kind()
+}
+
+func (t TNamed) Type() string {
+	return t.Typ
+}
+
+func (t TNamed) Format(v interface{}) string {
+	return fmt.Sprintf("%s(%s)", t.Typ, t.ETyp.Format(v))
+}